@@ -0,0 +1,292 @@
+package oas
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// BodyOption configures how request and response bodies are validated.
+type BodyOption func(*bodyOptions)
+
+type bodyOptions struct {
+	strict        bool
+	stopOnFailure bool
+	maxFileSize   int64
+	maxParts      int
+	maxBodySize   int64
+}
+
+// defaultMaxBodySize is the size limit of a body when WithMaxBodySize is not given.
+const defaultMaxBodySize = 64 << 20
+
+// WithMaxBodySize limits the size of a body, in bytes. The default is 64 MiB.
+func WithMaxBodySize(size int64) BodyOption {
+	return func(o *bodyOptions) {
+		o.maxBodySize = size
+	}
+}
+
+// StrictBody rejects object properties the schema does not declare.
+func StrictBody() BodyOption {
+	return func(o *bodyOptions) {
+		o.strict = true
+	}
+}
+
+// StopOnFirstFailure stops validating a body at its first failure.
+func StopOnFirstFailure() BodyOption {
+	return func(o *bodyOptions) {
+		o.stopOnFailure = true
+	}
+}
+
+// ValidateRequestBody validates the body of a request to op sent with the given Content-Type.
+// The media type is chosen with MatchContentType. JSON bodies are validated against its
+// schema as a request, so readOnly properties are rejected; text bodies are validated as a
+// string, XML bodies are decoded with DecodeXML, and application/x-www-form-urlencoded and
+// multipart/form-data bodies are decoded according to the Encoding of the media type and
+// validated as an object. Bodies of other media types are only checked to be allowed.
+// The schema is compiled on first use and cached on the document; see ResetValidators.
+func (o *OpenAPI) ValidateRequestBody(op *Operation, contentType string, body []byte, opts ...BodyOption) error {
+	return o.ValidateRequestBodyReader(op, contentType, bytes.NewReader(body), opts...)
+}
+
+// ValidateRequestBodyReader is ValidateRequestBody for a body read from r, such as the Body
+// of an http.Request. Reading stops with an error as soon as the body exceeds the size
+// WithMaxBodySize sets, a multipart body the number of parts WithMaxParts sets, or one of
+// its parts the size WithMaxFileSize sets. The decoded parts are held in memory until the
+// body is validated.
+func (o *OpenAPI) ValidateRequestBodyReader(op *Operation, contentType string, r io.Reader, opts ...BodyOption) error {
+	body := bufio.NewReader(r)
+	_, err := body.Peek(1)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("error reading request body: %w", err)
+	}
+	empty := err == io.EOF
+	if op == nil || op.RequestBody == nil {
+		if !empty {
+			return errors.New("operation does not accept a request body")
+		}
+		return nil
+	}
+	requestBody := op.RequestBody
+	if requestBody.Ref != "" {
+		target, err := o.resolveRef(requestBody.Ref)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if requestBody, ok = target.(*RequestBody); !ok {
+			return fmt.Errorf("reference '%s' is not a request body", op.RequestBody.Ref)
+		}
+	}
+	if empty {
+		if requestBody.Required {
+			return errors.New("request body is required")
+		}
+		return nil
+	}
+	return o.validateBody(requestBody.Content, contentType, body, RequestDirection, opts)
+}
+
+// ValidateResponseBody validates the body of a response of op with the given status code and
+// Content-Type. The response is the one declared for the status code, for its range, such as
+// "4XX", or the default one. Bodies are validated as ValidateRequestBody does, as a response,
+// so writeOnly properties are rejected.
+func (o *OpenAPI) ValidateResponseBody(op *Operation, status int, contentType string, body []byte, opts ...BodyOption) error {
+	if op == nil {
+		return errors.New("operation is nil")
+	}
+	response := op.Responses[strconv.Itoa(status)]
+	if response == nil {
+		response = op.Responses[strconv.Itoa(status/100)+"XX"]
+	}
+	if response == nil {
+		response = op.Responses["default"]
+	}
+	if response == nil {
+		return fmt.Errorf("operation does not declare a response for status %d", status)
+	}
+	if response.Ref != "" {
+		target, err := o.resolveRef(response.Ref)
+		if err != nil {
+			return err
+		}
+		resolved, ok := target.(*Response)
+		if !ok {
+			return fmt.Errorf("reference '%s' is not a response", response.Ref)
+		}
+		response = resolved
+	}
+	if len(response.Content) == 0 || len(body) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("response for status %d does not declare a body", status)
+		}
+		return nil
+	}
+	return o.validateBody(response.Content, contentType, bytes.NewReader(body), ResponseDirection, opts)
+}
+
+// validateBody validates a body against the media type of content matching contentType.
+// Multipart bodies are decoded from r as they are read; other bodies are read fully first.
+func (o *OpenAPI) validateBody(content map[string]*MediaType, contentType string, r io.Reader, direction Direction, opts []BodyOption) error {
+	options := &bodyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	_, mediaType, ok := MatchContentType(content, contentType)
+	if !ok {
+		return fmt.Errorf("content type '%s' is not allowed", contentType)
+	}
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+	maxBodySize := options.maxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+	limited := &maxBytesReader{r: r, remaining: maxBodySize, limit: maxBodySize}
+	r = limited
+	t, _ := parseMediaRange(contentType)
+	multipartForm := t.typ == "multipart" && t.subtype == "form-data"
+	var body []byte
+	if !multipartForm {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("error reading body: %w", err)
+		}
+		body = data
+	}
+	var value interface{}
+	var partErrs ValidationErrors
+	switch {
+	case t.isXML():
+		decoded, err := o.DecodeXML(mediaType.Schema, body)
+		if err != nil {
+			return err
+		}
+		value = decoded
+	case t.isJSON(), t.typ == "text":
+	case t.typ == "application" && t.subtype == "x-www-form-urlencoded":
+		object, err := o.decodeURLEncoded(mediaType, body)
+		if err != nil {
+			return err
+		}
+		value = object
+	case multipartForm:
+		object, err := o.decodeMultipart(mediaType, contentType, r, options)
+		if limited.remaining < 0 {
+			// The multipart reader may report the truncated body as malformed instead.
+			return limited.err()
+		}
+		if err != nil && !errors.As(err, &partErrs) {
+			return err
+		}
+		value = object
+	default:
+		return nil
+	}
+
+	v, err := o.validator(mediaType.Schema, direction)
+	if err != nil {
+		return err
+	}
+	switch {
+	case t.isJSON():
+		err = v.ValidateJSON(body, options.strict, options.stopOnFailure)
+	case t.typ == "text" && !t.isXML():
+		err = v.Validate(string(body), options.strict, options.stopOnFailure)
+	default:
+		if options.stopOnFailure && len(partErrs) > 0 {
+			return partErrs[:1]
+		}
+		err = v.Validate(value, options.strict, options.stopOnFailure)
+	}
+	if len(partErrs) == 0 {
+		return err
+	}
+	var errs ValidationErrors
+	if errors.As(err, &errs) {
+		partErrs = append(partErrs, errs...)
+	}
+	return partErrs
+}
+
+// validatorCache holds the validators a document compiled for its body and part header schemas.
+type validatorCache struct {
+	mu         sync.Mutex
+	validators map[validatorKey]*Validator
+}
+
+type validatorKey struct {
+	schema    *Schema
+	direction Direction
+}
+
+// validatorCachesMu guards the creation of the validator cache of every document.
+var validatorCachesMu sync.Mutex
+
+// validator returns the Validator of schema, a schema of the document, for values sent in
+// direction. It is compiled on first use and cached on the document.
+func (o *OpenAPI) validator(schema *Schema, direction Direction) (*Validator, error) {
+	validatorCachesMu.Lock()
+	if o.validators == nil {
+		o.validators = &validatorCache{validators: map[validatorKey]*Validator{}}
+	}
+	cache := o.validators
+	validatorCachesMu.Unlock()
+
+	key := validatorKey{schema, direction}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if v, ok := cache.validators[key]; ok {
+		return v, nil
+	}
+	v, err := Compile(schema, WithComponents(o.Components), WithDirection(direction))
+	if err != nil {
+		return nil, err
+	}
+	cache.validators[key] = v
+	return v, nil
+}
+
+// ResetValidators drops the validators the document compiled to validate bodies and part
+// headers. Call it after changing a reference or a pattern of a schema, or replacing a
+// schema, of a document that has validated bodies; the cached validators keep the
+// references and patterns they were compiled with.
+func (o *OpenAPI) ResetValidators() {
+	validatorCachesMu.Lock()
+	defer validatorCachesMu.Unlock()
+	o.validators = nil
+}
+
+// maxBytesReader reads from r and fails once more than limit bytes have been read.
+type maxBytesReader struct {
+	r         io.Reader
+	remaining int64 // The bytes left before the limit, negative once it is exceeded.
+	limit     int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.remaining < 0 {
+		return 0, m.err()
+	}
+	if int64(len(p)) > m.remaining+1 {
+		p = p[:m.remaining+1]
+	}
+	n, err := m.r.Read(p)
+	m.remaining -= int64(n)
+	if m.remaining < 0 {
+		return n, m.err()
+	}
+	return n, err
+}
+
+func (m *maxBytesReader) err() error {
+	return fmt.Errorf("body exceeds the maximum size of %d bytes", m.limit)
+}
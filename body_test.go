@@ -0,0 +1,121 @@
+package oas
+
+import (
+	"strings"
+	"testing"
+)
+
+const petDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Pets", "version": "1"},
+	"paths": {"/pets": {"post": {
+		"requestBody": {"required": true, "content": {
+			"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}},
+			"application/x-www-form-urlencoded": {"schema": {"$ref": "#/components/schemas/Pet"}},
+			"text/plain": {"schema": {"type": "string", "maxLength": 4}}
+		}},
+		"responses": {
+			"201": {"description": "created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+			"4XX": {"description": "rejected", "content": {"application/json": {"schema": {"type": "object", "required": ["error"]}}}}
+		}
+	}}},
+	"components": {"schemas": {"Pet": {
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"id": {"type": "integer", "readOnly": true},
+			"name": {"type": "string"},
+			"secret": {"type": "string", "writeOnly": true}
+		}
+	}}}
+}`
+
+func TestValidateRequestBody(t *testing.T) {
+	doc, err := LoadJSON([]byte(petDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/pets"].Post
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []BodyOption
+		err         string // A substring of the expected error, or empty when the body is valid.
+	}{
+		{name: "JSON", contentType: "application/json", body: `{"name": "Rex", "secret": "s"}`},
+		{name: "JSON with parameters", contentType: "application/json; charset=utf-8", body: `{"name": "Rex"}`},
+		{name: "missing property", contentType: "application/json", body: `{}`, err: "name"},
+		{name: "readOnly property", contentType: "application/json", body: `{"name": "Rex", "id": 1}`, err: "id"},
+		{name: "unknown property", contentType: "application/json", body: `{"name": "Rex", "age": 1}`, opts: []BodyOption{StrictBody()}, err: "age"},
+		{name: "form", contentType: "application/x-www-form-urlencoded", body: "name=Rex"},
+		{name: "invalid form", contentType: "application/x-www-form-urlencoded", body: "id=1", err: "name"},
+		{name: "text", contentType: "text/plain", body: "Rex"},
+		{name: "text too long", contentType: "text/plain", body: "Rexie", err: "length"},
+		{name: "media type not allowed", contentType: "application/xml", body: "<pet/>", err: "not allowed"},
+		{name: "body larger than the maximum size", contentType: "application/json", body: `{"name": "Rex"}`, opts: []BodyOption{WithMaxBodySize(8)}, err: "maximum size of 8 bytes"},
+		{name: "missing body", contentType: "application/json", err: "required"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := doc.ValidateRequestBody(op, test.contentType, []byte(test.body), test.opts...)
+			if test.err == "" && err != nil {
+				t.Errorf("ValidateRequestBody() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("ValidateRequestBody() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidateResponseBody(t *testing.T) {
+	doc, err := LoadJSON([]byte(petDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/pets"].Post
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    string // A substring of the expected error, or empty when the body is valid.
+	}{
+		{name: "created", status: 201, body: `{"id": 1, "name": "Rex"}`},
+		{name: "writeOnly property", status: 201, body: `{"name": "Rex", "secret": "s"}`, err: "secret"},
+		{name: "status range", status: 404, body: `{"error": "not found"}`},
+		{name: "invalid status range body", status: 404, body: `{}`, err: "error"},
+		{name: "undeclared status", status: 500, body: `{}`, err: "status 500"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := doc.ValidateResponseBody(op, test.status, "application/json", []byte(test.body))
+			if test.err == "" && err != nil {
+				t.Errorf("ValidateResponseBody() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("ValidateResponseBody() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidatorCache(t *testing.T) {
+	doc, err := LoadJSON([]byte(petDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := doc.Paths["/pets"].Post.RequestBody.Content["application/json"].Schema
+	request, _ := doc.validator(schema, RequestDirection)
+	if again, _ := doc.validator(schema, RequestDirection); again != request {
+		t.Error("validator() compiled the request schema again")
+	}
+	if response, _ := doc.validator(schema, ResponseDirection); response == request {
+		t.Error("validator() shared a validator between directions")
+	}
+
+	doc.ResetValidators()
+	if again, _ := doc.validator(schema, RequestDirection); again == request {
+		t.Error("validator() after ResetValidators() returned the dropped validator")
+	}
+}
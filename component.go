@@ -2,21 +2,24 @@ package oas
 
 // RequestBody represents a request body object in OpenAPI
 type RequestBody struct {
-	Description string                `json:"description,omitempty" yaml:"description"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
 	Content     map[string]*MediaType `json:"content" yaml:"content"`
-	Required    bool                  `json:"required,omitempty" yaml:"required"`
+	Required    bool                  `json:"required,omitempty" yaml:"required,omitempty"`
 	Ref         string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Extensions  Extensions            `json:"-" yaml:"-"`
 }
 
 // Components represent the component object in OpenAPI
 type Components struct {
-	Schemas         map[string]*Schema         `json:"schemas,omitempty" yaml:"schemas"`
-	Responses       map[string]*Response       `json:"responses,omitempty" yaml:"responses"`
-	Parameters      map[string]*Parameter      `json:"parameters,omitempty" yaml:"parameters"`
-	Examples        map[string]*Example        `json:"examples,omitempty" yaml:"examples"`
-	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty" yaml:"requestBodies"`
-	Headers         map[string]*Header         `json:"headers,omitempty" yaml:"headers"`
-	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes"`
-	Links           map[string]*Link           `json:"links,omitempty" yaml:"links"`
-	Callbacks       map[string]*Callback       `json:"callbacks,omitempty" yaml:"callbacks"`
+	Schemas         map[string]*Schema         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty" yaml:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Examples        map[string]*Example        `json:"examples,omitempty" yaml:"examples,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty" yaml:"requestBodies,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty" yaml:"headers,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+	Links           map[string]*Link           `json:"links,omitempty" yaml:"links,omitempty"`
+	Callbacks       map[string]*Callback       `json:"callbacks,omitempty" yaml:"callbacks,omitempty"`
+	PathItems       map[string]*PathItem       `json:"pathItems,omitempty" yaml:"pathItems,omitempty"`
+	Extensions      Extensions                 `json:"-" yaml:"-"`
 }
@@ -1,279 +1,256 @@
 package oas
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-// Dereference replaces all $ref fields in the OpenAPI struct with the actual referenced objects.
+// Dereference returns a copy of the document in which every local $ref to a component has been
+// replaced by the referenced object. References that cannot be resolved are left in place and
+// reported in the returned error. The original document is not modified.
 func (o OpenAPI) Dereference() (*OpenAPI, error) {
 	// Create a deep copy of the OpenAPI struct to avoid modifying the original
-	dereferenced := o
+	dereferenced := clone(&o)
 
-	// Dereference Paths
-	for pathKey, pathItem := range dereferenced.Paths {
-		if pathItem.Ref != "" {
-			refPath, err := resolveRefPath(pathItem.Ref, dereferenced.Components)
-			if err != nil {
-				return nil, err
-			}
-			dereferenced.Paths[pathKey] = refPath
-		} else {
-			dereferenced.Paths[pathKey] = pathItem.dereference(dereferenced.Components)
+	var errs []error
+	Walk(dereferenced, Visitor{Enter: func(c *Cursor) WalkAction {
+		ref := refOf(c.Value)
+		if !strings.HasPrefix(ref, "#/") {
+			return WalkContinue
 		}
-	}
-
-	// Dereference Components (schemas, responses, parameters, etc.)
-	if dereferenced.Components != nil {
-		dereferenced.Components = dereferenced.Components.dereference()
-	}
-
-	return &dereferenced, nil
+		target, err := dereferenced.resolveRef(ref)
+		if err == nil {
+			err = c.Replace(target)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Pointer, err))
+			return WalkSkip
+		}
+		return WalkContinue
+	}})
+	return dereferenced, errors.Join(errs...)
 }
 
-// Dereference method for Path struct
-func (p *Path) dereference(components *Components) *Path {
-	if p == nil {
-		return nil
-	}
-	// Create a copy of the Path to avoid modifying the original
-	dereferenced := *p
-
-	// Dereference each operation (GET, POST, etc.)
-	if p.Get != nil {
-		dereferenced.Get = p.Get.dereference(components)
-	}
-	if p.Put != nil {
-		dereferenced.Put = p.Put.dereference(components)
-	}
-	if p.Post != nil {
-		dereferenced.Post = p.Post.dereference(components)
-	}
-	if p.Delete != nil {
-		dereferenced.Delete = p.Delete.dereference(components)
-	}
-	if p.Options != nil {
-		dereferenced.Options = p.Options.dereference(components)
-	}
-	if p.Head != nil {
-		dereferenced.Head = p.Head.dereference(components)
-	}
-	if p.Patch != nil {
-		dereferenced.Patch = p.Patch.dereference(components)
-	}
-	if p.Trace != nil {
-		dereferenced.Trace = p.Trace.dereference(components)
+// resolveRef returns the component a local $ref points to, following chained references.
+func (o *OpenAPI) resolveRef(ref string) (interface{}, error) {
+	for i := 0; i < 32; i++ {
+		target := o.lookupRef(ref)
+		if target == nil {
+			return nil, fmt.Errorf("reference '%s' not found", ref)
+		}
+		next := refOf(target)
+		if next == "" || !strings.HasPrefix(next, "#/") {
+			return target, nil
+		}
+		ref = next
 	}
-
-	return &dereferenced
+	return nil, fmt.Errorf("reference '%s' is circular", ref)
 }
 
-// Dereference method for Operation struct
-func (o *Operation) dereference(components *Components) *Operation {
-	if o == nil {
+// lookupRef returns the node a local reference points to, such as
+// "#/components/schemas/Pet" or "#/components/schemas/Pet/$defs/Tag", or nil.
+func (o *OpenAPI) lookupRef(ref string) interface{} {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
 		return nil
 	}
-	// Create a copy of the Operation to avoid modifying the original
-	dereferenced := *o
-
-	// Dereference RequestBody
-	if o.RequestBody != nil {
-		dereferenced.RequestBody = o.RequestBody.dereference(components)
-	}
-
-	// Dereference Responses
-	for statusCode, response := range dereferenced.Responses {
-		dereferenced.Responses[statusCode] = response.dereference(components)
-	}
-
-	// Dereference Parameters
-	for i, parameter := range dereferenced.Parameters {
-		dereferenced.Parameters[i] = parameter.dereference(components)
-	}
-
-	return &dereferenced
+	return lookupPointer(o, pointer)
 }
 
-// Dereference method for Components struct
-func (c *Components) dereference() *Components {
-	if c == nil {
+// lookupPointer returns the node a JSON pointer designates below root, or nil. Struct fields
+// are selected by their json names, "x-" tokens select extensions and the other tokens of a
+// Callback select its expressions. Structs are returned as pointers.
+func lookupPointer(root interface{}, pointer string) interface{} {
+	tokens, err := pointerTokens(pointer)
+	if err != nil {
 		return nil
 	}
-	// Create a copy of the Components to avoid modifying the original
-	dereferenced := *c
-
-	// Dereference Schemas
-	for schemaKey, schema := range dereferenced.Schemas {
-		if schema.Ref != "" {
-			dereferenced.Schemas[schemaKey] = schema.dereference(&dereferenced)
+	v := reflect.ValueOf(root)
+	for _, token := range tokens {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
 		}
-	}
-
-	// Dereference Responses
-	for responseKey, response := range dereferenced.Responses {
-		if response.Ref != "" {
-			dereferenced.Responses[responseKey] = response.dereference(&dereferenced)
+		switch v.Kind() {
+		case reflect.Struct:
+			if callback, ok := v.Addr().Interface().(*Callback); ok && token != "$ref" && !isExtension(token) {
+				v = mapIndex(reflect.ValueOf(callback.Expression), token)
+				break
+			}
+			if isExtension(token) {
+				if extensions := v.FieldByName("Extensions"); extensions.IsValid() && extensions.Kind() == reflect.Map {
+					v = mapIndex(extensions, token)
+					break
+				}
+			}
+			v = fieldByJSONName(v, token)
+		case reflect.Map:
+			if v.IsNil() {
+				return nil
+			}
+			v = mapIndex(v, token)
+		case reflect.Slice:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= v.Len() {
+				return nil
+			}
+			v = v.Index(i)
+		default:
+			return nil
 		}
-	}
-
-	// Dereference Parameters
-	for parameterKey, parameter := range dereferenced.Parameters {
-		if parameter.Ref != "" {
-			dereferenced.Parameters[parameterKey] = parameter.dereference(&dereferenced)
+		if !v.IsValid() {
+			return nil
 		}
 	}
-
-	// Dereference RequestBodies
-	for requestBodyKey, requestBody := range dereferenced.RequestBodies {
-		if requestBody.Ref != "" {
-			dereferenced.RequestBodies[requestBodyKey] = requestBody.dereference(&dereferenced)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
 		}
-	}
-
-	// Dereference Headers
-	for headerKey, header := range dereferenced.Headers {
-		if header.Ref != "" {
-			dereferenced.Headers[headerKey] = header.dereference(&dereferenced)
+	case reflect.Struct:
+		if v.CanAddr() {
+			return v.Addr().Interface()
 		}
 	}
-
-	// Dereference SecuritySchemes
-	for securitySchemeKey, securityScheme := range dereferenced.SecuritySchemes {
-		if securityScheme.Ref != "" {
-			dereferenced.SecuritySchemes[securitySchemeKey] = securityScheme.dereference(&dereferenced)
-		}
-	}
-
-	return &dereferenced
+	return v.Interface()
 }
 
-// Dereference method for Schema struct
-func (s *Schema) dereference(components *Components) *Schema {
-	if s == nil || components == nil {
-		return s
-	}
-	if s.Ref != "" {
-		referencedSchema, found := components.Schemas[getComponentName(s.Ref)]
-		if found {
-			return referencedSchema.dereference(components)
+// fieldByJSONName returns the field of a struct with the given json name, or the zero Value.
+func fieldByJSONName(v reflect.Value, name string) reflect.Value {
+	for _, field := range jsonFields(v.Type()) {
+		if field.name == name {
+			return v.FieldByIndex(field.index)
 		}
-		fmt.Printf("Error: Schema ref '%s' not found.\n", s.Ref)
 	}
-	return s
+	return reflect.Value{}
 }
 
-// Dereference method for Response struct
-func (r *Response) dereference(components *Components) *Response {
-	if r == nil || components == nil {
-		return r
+// pointerTokens splits a JSON pointer into its unescaped reference tokens.
+func pointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
 	}
-	if r.Ref != "" {
-		referencedResponse, found := components.Responses[getComponentName(r.Ref)]
-		if found {
-			return referencedResponse.dereference(components)
-		}
-		fmt.Printf("Error: Response ref '%s' not found.\n", r.Ref)
-	}
-	return r
-}
-
-// Dereference method for Parameter struct
-func (p *Parameter) dereference(components *Components) *Parameter {
-	if p == nil || components == nil {
-		return p
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer '%s' does not start with '/'", pointer)
 	}
-	if p.Ref != "" {
-		referencedParameter, found := components.Parameters[getComponentName(p.Ref)]
-		if found {
-			return referencedParameter.dereference(components)
-		}
-		fmt.Printf("Error: Parameter ref '%s' not found.\n", p.Ref)
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescape(token)
 	}
-	return p
+	return tokens, nil
 }
 
-// Dereference method for RequestBody struct
-func (rb *RequestBody) dereference(components *Components) *RequestBody {
-	if rb == nil || components == nil {
-		return rb
-	}
-	if rb.Ref != "" {
-		referencedRequestBody, found := components.RequestBodies[getComponentName(rb.Ref)]
-		if found {
-			return referencedRequestBody.dereference(components)
-		}
-		fmt.Printf("Error: RequestBody ref '%s' not found.\n", rb.Ref)
-	}
-	return rb
+// refOf returns the $ref of a node visited by Walk, or "" if it has none.
+func refOf(node interface{}) string {
+	switch n := node.(type) {
+	case *Path:
+		return n.Ref
+	case *Schema:
+		return n.Ref
+	case *Parameter:
+		return n.Ref
+	case *RequestBody:
+		return n.Ref
+	case *Response:
+		return n.Ref
+	case *Header:
+		return n.Ref
+	case *Example:
+		return n.Ref
+	case *Link:
+		return n.Ref
+	case *Callback:
+		return n.Ref
+	case *SecurityScheme:
+		return n.Ref
+	}
+	return ""
 }
 
-// Dereference method for Header struct
-func (h *Header) dereference(components *Components) *Header {
-	if h == nil || components == nil {
-		return h
-	}
-	if h.Ref != "" {
-		referencedHeader, found := components.Headers[getComponentName(h.Ref)]
-		if found {
-			return referencedHeader.dereference(components)
-		}
-		fmt.Printf("Error: Header ref '%s' not found.\n", h.Ref)
-	}
-	return h
+// clone returns a deep copy of v. Pointers and maps shared within v are shared within the copy.
+func clone[T any](v T) T {
+	c := &cloner{seen: map[cloneKey]reflect.Value{}}
+	return c.copy(reflect.ValueOf(v)).Interface().(T)
 }
 
-// Dereference method for SecurityScheme struct
-func (ss *SecurityScheme) dereference(components *Components) *SecurityScheme {
-	if ss == nil || components == nil {
-		return ss
-	}
-	if ss.Ref != "" {
-		referencedSecurityScheme, found := components.SecuritySchemes[getComponentName(ss.Ref)]
-		if found {
-			return referencedSecurityScheme.dereference(components)
-		}
-		fmt.Printf("Error: SecurityScheme ref '%s' not found.\n", ss.Ref)
-	}
-	return ss
+type cloneKey struct {
+	ptr uintptr
+	typ reflect.Type
 }
 
-// resolveRefPath is a helper function to resolve $ref strings within components for Path objects
-func resolveRefPath(ref string, components *Components) (*Path, error) {
-	refComponent := getComponentType(ref)
-	refName := getComponentName(ref)
+type cloner struct {
+	seen map[cloneKey]reflect.Value
+}
 
-	switch strings.ToLower(refComponent) {
-	case "schemas":
-		components.Schemas[refName].dereference(components)
-	case "responses":
-		components.Responses[refName].dereference(components)
-	case "parameters":
-		components.Parameters[refName].dereference(components)
-	case "requestbodies":
-		components.RequestBodies[refName].dereference(components)
-	case "headers":
-		components.Headers[refName].dereference(components)
-	case "securityschemes":
-		components.SecuritySchemes[refName].dereference(components)
-	default:
-		return nil, fmt.Errorf("reference '%s' not found", ref)
+func (c *cloner) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{v.Pointer(), v.Type()}
+		if p, ok := c.seen[key]; ok {
+			return p
+		}
+		p := reflect.New(v.Type().Elem())
+		c.seen[key] = p
+		p.Elem().Set(c.copy(v.Elem()))
+		return p
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		// Copy unexported fields as they are, then replace the exported ones with copies.
+		s.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if s.Field(i).CanSet() {
+				s.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := cloneKey{v.Pointer(), v.Type()}
+		if m, ok := c.seen[key]; ok {
+			return m
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		c.seen[key] = m
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return s
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		i := reflect.New(v.Type()).Elem()
+		i.Set(c.copy(v.Elem()))
+		return i
 	}
-	return nil, fmt.Errorf("reference '%s' not found", ref)
+	return v
 }
 
 var (
 	componentRegex = regexp.MustCompile(`^#/components/(.*)/(.*)$`)
 )
 
-func getComponentType(ref string) string {
-	matches := componentRegex.FindStringSubmatch(ref)
-	if len(matches) != 3 {
-		return ""
-	}
-	return matches[1]
-}
-
 func getComponentName(ref string) string {
 	matches := componentRegex.FindStringSubmatch(ref)
 	if len(matches) != 3 {
@@ -0,0 +1,374 @@
+package oas
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DispatchOption configures a CallbackDispatcher.
+type DispatchOption func(*CallbackDispatcher)
+
+// WithHTTPClient sends callback requests with client instead of a client that times out
+// after 30s.
+func WithHTTPClient(client *http.Client) DispatchOption {
+	return func(d *CallbackDispatcher) {
+		d.client = client
+	}
+}
+
+// WithRetries sets how many times a callback request is attempted in total; the default is 3.
+func WithRetries(attempts int) DispatchOption {
+	return func(d *CallbackDispatcher) {
+		d.attempts = attempts
+	}
+}
+
+// WithBackoff sets the delay before the first retry, doubled for each further retry up to
+// max. The defaults are 500ms and 30s.
+func WithBackoff(initial, max time.Duration) DispatchOption {
+	return func(d *CallbackDispatcher) {
+		d.backoff, d.maxBackoff = initial, max
+	}
+}
+
+// WithRequestEditor calls edit on every callback request before it is sent, for example to
+// add authentication or a signature header. An error aborts the callback.
+func WithRequestEditor(edit func(r *http.Request) error) DispatchOption {
+	return func(d *CallbackDispatcher) {
+		d.edit = edit
+	}
+}
+
+// WithMaxResponseSize sets the maximum size of the body of a callback response; the
+// default is 1 MiB. A larger response fails the callback.
+func WithMaxResponseSize(n int64) DispatchOption {
+	return func(d *CallbackDispatcher) {
+		d.maxResponseSize = n
+	}
+}
+
+// WithURLCheck calls check on the URL of every callback request, and of every redirect it
+// follows, before it is sent. An error aborts the callback. Callback URLs usually come from
+// the requests the API receives, so a server that dispatches callbacks should use check to
+// reject the hosts its callers must not reach, such as loopback and private addresses.
+func WithURLCheck(check func(u *url.URL) error) DispatchOption {
+	return func(d *CallbackDispatcher) {
+		d.checkURL = check
+	}
+}
+
+// CallbackDispatcher sends the requests the callbacks of operations describe.
+type CallbackDispatcher struct {
+	Document *OpenAPI
+
+	client          *http.Client
+	attempts        int
+	backoff         time.Duration
+	maxBackoff      time.Duration
+	maxResponseSize int64
+	edit            func(r *http.Request) error
+	checkURL        func(u *url.URL) error
+}
+
+const (
+	defaultCallbackTimeout = 30 * time.Second
+	defaultMaxResponseSize = 1 << 20
+	maxCallbackRedirects   = 10
+)
+
+// NewCallbackDispatcher returns a CallbackDispatcher for the callbacks of o.
+func NewCallbackDispatcher(o *OpenAPI, opts ...DispatchOption) *CallbackDispatcher {
+	d := &CallbackDispatcher{
+		Document:        o,
+		client:          &http.Client{Timeout: defaultCallbackTimeout},
+		attempts:        3,
+		backoff:         500 * time.Millisecond,
+		maxBackoff:      30 * time.Second,
+		maxResponseSize: defaultMaxResponseSize,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.attempts < 1 {
+		d.attempts = 1
+	}
+	if d.checkURL != nil {
+		// Check redirects too, on a copy so that a client passed by the caller is left as is.
+		client := *d.client
+		next := client.CheckRedirect
+		client.CheckRedirect = func(r *http.Request, via []*http.Request) error {
+			if err := d.checkURL(r.URL); err != nil {
+				return &rejectedURLError{err}
+			}
+			if next != nil {
+				return next(r, via)
+			}
+			if len(via) >= maxCallbackRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxCallbackRedirects)
+			}
+			return nil
+		}
+		d.client = &client
+	}
+	return d
+}
+
+// CallbackResult is the outcome of one callback request.
+type CallbackResult struct {
+	Expression string      // The key of the callback the URL was computed from.
+	Method     string      // The HTTP method of the request.
+	URL        string      // The URL the request was sent to.
+	Attempts   int         // The number of times the request was sent.
+	StatusCode int         // The status code of the last response, or 0 when none was received.
+	Header     http.Header // The headers of the last response.
+	Body       []byte      // The body of the last response.
+	Err        error       // Why the callback failed, or nil.
+}
+
+// Dispatch sends the callback with the given name of op. Its URLs are computed from c, the
+// request op received and, usually, the response it returned. A request is sent for each
+// URL and each operation of the callback, with body encoded as the media type of the
+// operation's request body: JSON, XML following the schema, or as is when body is a []byte
+// or a string. The body is validated before it is sent; requests that fail with a network
+// error or a 408, 429 or 5xx status are retried with exponential backoff, honoring
+// Retry-After, and the last response is validated against the responses of the operation.
+// The returned error joins the errors of the results.
+//
+// Whoever calls the API therefore chooses where its callbacks are sent; see WithURLCheck.
+func (d *CallbackDispatcher) Dispatch(ctx context.Context, op *Operation, name string, c *RuntimeContext, body interface{}) ([]*CallbackResult, error) {
+	if op == nil || op.Callbacks[name] == nil {
+		return nil, fmt.Errorf("operation has no callback '%s'", name)
+	}
+	targets, err := d.Document.CallbackURLs(op.Callbacks[name], c)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*CallbackResult
+	var errs []error
+	for _, target := range targets {
+		pathItem := target.PathItem
+		if pathItem != nil && pathItem.Ref != "" {
+			resolved, err := d.Document.resolveRef(pathItem.Ref)
+			if err != nil {
+				return nil, fmt.Errorf("callback '%s': %w", target.Expression, err)
+			}
+			var ok bool
+			if pathItem, ok = resolved.(*PathItem); !ok {
+				return nil, fmt.Errorf("callback '%s': reference '%s' is not a path item", target.Expression, target.PathItem.Ref)
+			}
+		}
+		for _, method := range methods {
+			callbackOp := pathItem.Operation(method)
+			if callbackOp == nil {
+				continue
+			}
+			result := &CallbackResult{Expression: target.Expression, Method: strings.ToUpper(method), URL: target.URL}
+			result.Err = d.send(ctx, callbackOp, result, body)
+			if result.Err != nil {
+				errs = append(errs, fmt.Errorf("callback %s %s: %w", result.Method, result.URL, result.Err))
+			}
+			results = append(results, result)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// send encodes and validates the body of a callback request, sends it until it succeeds or
+// the attempts run out, and validates the last response.
+func (d *CallbackDispatcher) send(ctx context.Context, op *Operation, result *CallbackResult, body interface{}) error {
+	contentType, data, err := d.encodeBody(op, body)
+	if err != nil {
+		return err
+	}
+	if err := d.Document.ValidateRequestBody(op, contentType, data); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	for {
+		r, err := d.newRequest(ctx, result, contentType, data)
+		if err != nil {
+			return err
+		}
+		result.Attempts++
+		result.StatusCode, result.Header, result.Body = 0, nil, nil
+		resp, err := d.client.Do(r)
+		var rejected *rejectedURLError
+		if errors.As(err, &rejected) {
+			return rejected.err
+		}
+		if err == nil {
+			result.StatusCode, result.Header = resp.StatusCode, resp.Header
+			result.Body, err = io.ReadAll(io.LimitReader(resp.Body, d.maxResponseSize+1))
+			resp.Body.Close()
+			if err != nil {
+				err = fmt.Errorf("error reading response body: %w", err)
+			} else if int64(len(result.Body)) > d.maxResponseSize {
+				result.Body = result.Body[:d.maxResponseSize]
+				return fmt.Errorf("response body exceeds the maximum size of %d bytes", d.maxResponseSize)
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		retry := err != nil || retryableStatus(result.StatusCode)
+		if !retry || result.Attempts >= d.attempts {
+			if err != nil {
+				return err
+			}
+			break
+		}
+
+		delay := d.backoff << (result.Attempts - 1)
+		if delay > d.maxBackoff || delay <= 0 {
+			delay = d.maxBackoff
+		}
+		if after, ok := retryAfter(result.Header); ok && err == nil {
+			delay = min(after, d.maxBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if retryableStatus(result.StatusCode) {
+		return fmt.Errorf("callback returned status %d after %d attempts", result.StatusCode, result.Attempts)
+	}
+	if err := d.Document.ValidateResponseBody(op, result.StatusCode, result.Header.Get("Content-Type"), result.Body); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if result.StatusCode >= 400 {
+		return fmt.Errorf("callback returned status %d", result.StatusCode)
+	}
+	return nil
+}
+
+// rejectedURLError is the error of a redirect whose URL failed the URL check, which is not
+// worth retrying.
+type rejectedURLError struct {
+	err error
+}
+
+func (e *rejectedURLError) Error() string { return e.err.Error() }
+
+// newRequest builds a callback request, checks its URL and lets the request editor amend it.
+// Its errors are not worth retrying.
+func (d *CallbackDispatcher) newRequest(ctx context.Context, result *CallbackResult, contentType string, data []byte) (*http.Request, error) {
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+	r, err := http.NewRequestWithContext(ctx, result.Method, result.URL, reader)
+	if err != nil {
+		return nil, err
+	}
+	if d.checkURL != nil {
+		if err := d.checkURL(r.URL); err != nil {
+			return nil, err
+		}
+	}
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if d.edit != nil {
+		if err := d.edit(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// encodeBody encodes the body of a callback request as the media type of the request body
+// of op, preferring JSON.
+func (d *CallbackDispatcher) encodeBody(op *Operation, body interface{}) (string, []byte, error) {
+	if body == nil {
+		return "", nil, nil
+	}
+	requestBody := op.RequestBody
+	if requestBody != nil && requestBody.Ref != "" {
+		target, err := d.Document.resolveRef(requestBody.Ref)
+		if err != nil {
+			return "", nil, err
+		}
+		var ok bool
+		if requestBody, ok = target.(*RequestBody); !ok {
+			return "", nil, fmt.Errorf("reference '%s' is not a request body", op.RequestBody.Ref)
+		}
+	}
+	if requestBody == nil {
+		return "", nil, errors.New("callback operation does not accept a request body")
+	}
+
+	contentType := ""
+	for _, key := range sortedKeys(requestBody.Content) {
+		t, ok := parseMediaRange(key)
+		if !ok || t.typ == "*" || t.subtype == "*" {
+			continue
+		}
+		if contentType == "" || t.isJSON() {
+			contentType = key
+		}
+		if t.isJSON() {
+			break
+		}
+	}
+	if contentType == "" {
+		return "", nil, errors.New("callback request body declares no concrete media type")
+	}
+
+	t, _ := parseMediaRange(contentType)
+	switch raw := body.(type) {
+	case []byte:
+		return contentType, raw, nil
+	case string:
+		if !t.isJSON() {
+			return contentType, []byte(raw), nil
+		}
+	}
+	switch {
+	case t.isJSON():
+		data, err := json.Marshal(body)
+		return contentType, data, err
+	case t.isXML():
+		var schema *Schema
+		if mediaType := requestBody.Content[contentType]; mediaType != nil {
+			schema = mediaType.Schema
+		}
+		data, err := d.Document.EncodeXML(schema, body)
+		return contentType, data, err
+	}
+	return "", nil, fmt.Errorf("cannot encode a %T as %s", body, contentType)
+}
+
+// retryableStatus reports whether a response status is worth retrying.
+func retryableStatus(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter returns the delay a Retry-After header asks for.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,179 @@
+package oas
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const callbackDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Events", "version": "1"},
+	"paths": {"/subscriptions": {"post": {
+		"requestBody": {"content": {"application/json": {"schema": {"type": "object"}}}},
+		"responses": {"201": {"description": "subscribed"}},
+		"callbacks": {"onEvent": {"{$request.body#/callbackUrl}": {"post": {
+			"requestBody": {"required": true, "content": {"application/json": {"schema": {
+				"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}
+			}}}},
+			"responses": {"200": {"description": "received", "content": {"application/json": {"schema": {
+				"type": "object", "required": ["ok"], "properties": {"ok": {"type": "boolean"}}
+			}}}}}
+		}}}}
+	}}}
+}`
+
+func TestCallbackDispatcher(t *testing.T) {
+	doc, err := LoadJSON([]byte(callbackDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/subscriptions"].Post
+
+	tests := []struct {
+		name     string
+		body     interface{}
+		statuses []int  // The statuses the server answers with, in order, 0 closing the connection; the last one repeats.
+		response string // The body of a 200 response.
+		opts     []DispatchOption
+		attempts int
+		status   int    // The status code of the result.
+		err      string // A substring of the expected error, or empty when the callback succeeds.
+		minDelay time.Duration
+	}{
+		{name: "delivered", body: map[string]interface{}{"id": "e1"}, statuses: []int{200}, response: `{"ok": true}`, attempts: 1, status: 200},
+		{
+			name:     "retried after Retry-After",
+			body:     map[string]interface{}{"id": "e1"},
+			statuses: []int{503, 200},
+			response: `{"ok": true}`,
+			attempts: 2,
+			status:   200,
+			minDelay: 50 * time.Millisecond, // Retry-After asks for 1s, capped by the maximum backoff.
+		},
+		{name: "unavailable", body: map[string]interface{}{"id": "e1"}, statuses: []int{503}, attempts: 3, status: 503, err: "status 503 after 3 attempts"},
+		{name: "connection closed after a retryable status", body: map[string]interface{}{"id": "e1"}, statuses: []int{503, 0}, attempts: 3, err: "EOF"},
+		{name: "invalid request body", body: map[string]interface{}{"name": "e1"}, err: "invalid request body"},
+		{name: "invalid response body", body: map[string]interface{}{"id": "e1"}, statuses: []int{200}, response: `{"ok": "yes"}`, attempts: 1, status: 200, err: "invalid response"},
+		{
+			name:     "response larger than the maximum size",
+			body:     map[string]interface{}{"id": "e1"},
+			statuses: []int{200},
+			response: `{"ok": true}`,
+			opts:     []DispatchOption{WithMaxResponseSize(4)},
+			attempts: 1,
+			status:   200,
+			err:      "maximum size of 4 bytes",
+		},
+		{
+			name: "request editor error",
+			body: map[string]interface{}{"id": "e1"},
+			opts: []DispatchOption{WithRequestEditor(func(r *http.Request) error {
+				return errors.New("no signing key")
+			})},
+			err: "no signing key",
+		},
+		{
+			name: "URL rejected",
+			body: map[string]interface{}{"id": "e1"},
+			opts: []DispatchOption{WithURLCheck(func(u *url.URL) error {
+				return errors.New("host " + u.Hostname() + " is blocked")
+			})},
+			err: "host 127.0.0.1 is blocked",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(hits.Add(1))
+				if r.URL.Path != "/hooks/1" || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("request to %s with Content-Type %q, want /hooks/1 with application/json", r.URL.Path, r.Header.Get("Content-Type"))
+				}
+				if body, _ := io.ReadAll(r.Body); !strings.Contains(string(body), `"id":"e1"`) {
+					t.Errorf("request body = %s, want the event", body)
+				}
+				status := test.statuses[min(n, len(test.statuses))-1]
+				if status == 0 {
+					conn, _, _ := w.(http.Hijacker).Hijack()
+					conn.Close()
+					return
+				}
+				if status != http.StatusOK {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			subscription := httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(`{"callbackUrl": "`+server.URL+`/hooks/1"}`))
+			subscription.Header.Set("Content-Type", "application/json")
+			d := NewCallbackDispatcher(doc, append([]DispatchOption{WithBackoff(time.Millisecond, 50*time.Millisecond)}, test.opts...)...)
+			start := time.Now()
+			results, err := d.Dispatch(context.Background(), op, "onEvent", &RuntimeContext{Request: subscription}, test.body)
+			elapsed := time.Since(start)
+
+			if test.err == "" && err != nil {
+				t.Fatalf("Dispatch() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("Dispatch() = %v, want an error containing %q", err, test.err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("len(results) = %d, want 1", len(results))
+			}
+			result := results[0]
+			if result.URL != server.URL+"/hooks/1" || result.Method != http.MethodPost {
+				t.Errorf("result = %s %s, want POST %s/hooks/1", result.Method, result.URL, server.URL)
+			}
+			if result.Attempts != test.attempts || int(hits.Load()) != test.attempts {
+				t.Errorf("attempts = %d, requests received = %d, want %d", result.Attempts, hits.Load(), test.attempts)
+			}
+			if result.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", result.StatusCode, test.status)
+			}
+			if elapsed < test.minDelay {
+				t.Errorf("Dispatch() took %s, want at least %s", elapsed, test.minDelay)
+			}
+		})
+	}
+}
+
+func TestCallbackDispatcherRedirect(t *testing.T) {
+	doc, err := LoadJSON([]byte(callbackDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	subscription := httptest.NewRequest(http.MethodPost, "/subscriptions", strings.NewReader(`{"callbackUrl": "`+server.URL+`/hooks/1"}`))
+	subscription.Header.Set("Content-Type", "application/json")
+	d := NewCallbackDispatcher(doc, WithBackoff(time.Millisecond, time.Millisecond), WithURLCheck(func(u *url.URL) error {
+		if u.Path == "/internal" {
+			return errors.New("path /internal is blocked")
+		}
+		return nil
+	}))
+	results, err := d.Dispatch(context.Background(), doc.Paths["/subscriptions"].Post, "onEvent", &RuntimeContext{Request: subscription}, map[string]interface{}{"id": "e1"})
+	if err == nil || !strings.Contains(err.Error(), "path /internal is blocked") {
+		t.Fatalf("Dispatch() = %v, want the redirect to be blocked", err)
+	}
+	if results[0].Attempts != 1 || hits.Load() != 1 {
+		t.Errorf("attempts = %d, requests received = %d, want 1", results[0].Attempts, hits.Load())
+	}
+}
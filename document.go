@@ -0,0 +1,226 @@
+package oas
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an OpenAPI document loaded together with its YAML node tree, so that it can
+// be saved with the comments, key order, anchors and quoting of the original source.
+// Edits are made through OpenAPI; Save only rewrites the nodes whose values changed.
+type Document struct {
+	OpenAPI *OpenAPI // The typed model of the document.
+
+	node *yaml.Node
+}
+
+// LoadDocument parses a YAML or JSON document and keeps its node tree for Save.
+func LoadDocument(data []byte) (*Document, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	openAPI := &OpenAPI{}
+	if err := node.Decode(openAPI); err != nil {
+		return nil, err
+	}
+	openAPI.recordPositions(node, "")
+	return &Document{OpenAPI: openAPI, node: node}, nil
+}
+
+// Save encodes the document as YAML. Nodes whose values are unchanged keep their original
+// formatting and comments, new keys are appended after the existing ones and removed keys
+// are deleted.
+func (d *Document) Save() ([]byte, error) {
+	updated := &yaml.Node{}
+	if err := updated.Encode(d.OpenAPI); err != nil {
+		return nil, err
+	}
+	if d.node == nil || len(d.node.Content) == 0 {
+		d.node = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{updated}}
+	} else {
+		mergeNode(d.node.Content[0], updated, reflect.TypeOf(d.OpenAPI))
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indentOf(d.node.Content[0]))
+	if err := encoder.Encode(d.node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error encoding document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeNode updates dst, a node of the original tree, to hold the value of src, a node
+// encoded from a model value of type t, while keeping the formatting of every part of dst
+// that is unchanged.
+func mergeNode(dst, src *yaml.Node, t reflect.Type) {
+	if src.Kind == yaml.DocumentNode && len(src.Content) > 0 {
+		src = src.Content[0]
+	}
+	if sameValue(dst, src) {
+		return
+	}
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode && !hasMergeKey(dst):
+		mergeMapping(dst, src, t)
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				mergeNode(dst.Content[i], item, elemType(t))
+			} else {
+				dst.Content = append(dst.Content, item)
+			}
+		}
+		if len(dst.Content) > len(src.Content) {
+			dst.Content = dst.Content[:len(src.Content)]
+		}
+	default:
+		replaceNode(dst, src)
+	}
+}
+
+// mergeMapping merges the entries of src, encoded from a model value of type t, into dst.
+// A key missing from src is removed from dst only when the model holds it and it does not
+// hold a zero value, which the model omits when it is encoded. Keys the model does not hold,
+// such as fields it does not define, are kept as they are.
+func mergeMapping(dst, src *yaml.Node, t reflect.Type) {
+	index := map[string]int{}
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		index[dst.Content[i].Value] = i
+	}
+	present := map[string]bool{}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		present[key.Value] = true
+		if j, ok := index[key.Value]; ok {
+			mergeNode(dst.Content[j+1], value, keyType(t, key.Value))
+		} else {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+	content := dst.Content[:0]
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key := dst.Content[i].Value
+		if present[key] || isZeroNode(dst.Content[i+1]) || !modelsKey(t, key) {
+			content = append(content, dst.Content[i], dst.Content[i+1])
+		}
+	}
+	dst.Content = content
+}
+
+// modelsKey reports whether a value of type t holds the mapping key, so that a key missing
+// from its encoding was removed. Maps and untyped values hold every key; structs hold their
+// fields and extensions, and a Callback its expressions too.
+func modelsKey(t reflect.Type, key string) bool {
+	t = indirectType(t)
+	if t == nil || t.Kind() != reflect.Struct || t == reflect.TypeOf(Callback{}) || isExtension(key) {
+		return true
+	}
+	_, ok := yamlFields(t)[key]
+	return ok
+}
+
+// keyType returns the type of the value a mapping key holds in a value of type t, or nil when it is unknown.
+func keyType(t reflect.Type, key string) reflect.Type {
+	t = indirectType(t)
+	switch {
+	case t == nil || isExtension(key):
+		return nil
+	case t == reflect.TypeOf(Callback{}):
+		if key == "$ref" {
+			return nil
+		}
+		return reflect.TypeOf(PathItem{})
+	case t.Kind() == reflect.Map:
+		return t.Elem()
+	case t.Kind() == reflect.Struct:
+		return yamlFields(t)[key]
+	}
+	return nil
+}
+
+// elemType returns the type of the items of a slice type t, or nil when t is not a slice.
+func elemType(t reflect.Type) reflect.Type {
+	if t = indirectType(t); t != nil && t.Kind() == reflect.Slice {
+		return t.Elem()
+	}
+	return nil
+}
+
+// indirectType returns t without its pointers.
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// replaceNode replaces the value of dst with src, keeping the comments of dst.
+func replaceNode(dst, src *yaml.Node) {
+	head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+	*dst = *src
+	dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+}
+
+// sameValue reports whether two nodes decode to the same value.
+func sameValue(a, b *yaml.Node) bool {
+	var va, vb interface{}
+	if err := a.Decode(&va); err != nil {
+		return false
+	}
+	if err := b.Decode(&vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// isZeroNode reports whether a node holds null, false, zero, an empty string or an empty collection.
+func isZeroNode(node *yaml.Node) bool {
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+// hasMergeKey reports whether a mapping uses the "<<" merge key, whose entries cannot be
+// updated in place.
+func hasMergeKey(node *yaml.Node) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag == "!!merge" {
+			return true
+		}
+	}
+	return false
+}
+
+// indentOf returns the indentation of the first nested block mapping of node, or 2.
+func indentOf(node *yaml.Node) int {
+	if node.Kind != yaml.MappingNode {
+		return 2
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if value.Kind == yaml.MappingNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) > 0 {
+			if indent := value.Content[0].Column - key.Column; indent > 0 {
+				return indent
+			}
+		}
+	}
+	return 2
+}
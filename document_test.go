@@ -0,0 +1,86 @@
+package oas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocumentSave(t *testing.T) {
+	source := `openapi: 3.0.3
+info:
+  title: Pets # The public name.
+  version: "1"
+  x-audience: public
+paths:
+  /pets:
+    get:
+      summary: List pets
+      x-unmodelled-by-tools: kept
+      responses:
+        "200":
+          description: ok
+components:
+  schemas:
+    Pet:
+      type: object
+      $dynamicAnchor: pet
+      required: [name]
+`
+	tests := []struct {
+		name    string
+		edit    func(o *OpenAPI)
+		want    []string // Substrings of the saved document.
+		notWant []string // Substrings the saved document must not contain.
+	}{
+		{
+			name: "unchanged",
+			edit: func(o *OpenAPI) {},
+			want: []string{source},
+		},
+		{
+			name:    "cleared field",
+			edit:    func(o *OpenAPI) { o.Paths["/pets"].Get.Summary = "" },
+			want:    []string{"x-unmodelled-by-tools: kept", "title: Pets # The public name."},
+			notWant: []string{"summary:"},
+		},
+		{
+			name:    "removed extension",
+			edit:    func(o *OpenAPI) { delete(o.Info.Extensions, "x-audience") },
+			notWant: []string{"x-audience"},
+		},
+		{
+			name:    "unmodelled field of an edited schema",
+			edit:    func(o *OpenAPI) { o.Components.Schemas["Pet"].Required = nil },
+			want:    []string{"$dynamicAnchor: pet"},
+			notWant: []string{"required:"},
+		},
+		{
+			name: "added field",
+			edit: func(o *OpenAPI) { o.Info.Description = "All the pets." },
+			want: []string{"  x-audience: public\n  description: All the pets.\n"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := LoadDocument([]byte(source))
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.edit(doc.OpenAPI)
+			data, err := doc.Save()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("Save() =\n%s\nwant it to contain %q", data, want)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(string(data), notWant) {
+					t.Errorf("Save() =\n%s\nwant it not to contain %q", data, notWant)
+				}
+			}
+		})
+	}
+}
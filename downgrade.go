@@ -0,0 +1,756 @@
+package oas
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// openAPIConverter holds the state of an OpenAPI 3.x to Swagger 2.0 conversion.
+type openAPIConverter struct {
+	conversion
+	doc       *OpenAPI
+	bodyNames map[string]string // Maps the name of a request body component to its Swagger parameter name.
+	dropped   map[string]bool   // The security schemes that have no Swagger 2.0 equivalent.
+}
+
+// ToSwagger converts the document to Swagger 2.0. Every $ref is rewritten to point into
+// definitions, parameters or responses, and anything that cannot be represented in
+// Swagger 2.0, such as oneOf, multiple servers or cookie parameters, is reported.
+func (o *OpenAPI) ToSwagger() (*Swagger, []ConversionWarning) {
+	doc := clone(o)
+	c := &openAPIConverter{doc: doc, bodyNames: map[string]string{}, dropped: map[string]bool{}}
+	c.schemas()
+
+	s := &Swagger{
+		Swagger:      "2.0",
+		Info:         doc.Info,
+		Tags:         doc.Tags,
+		ExternalDocs: doc.ExternalDocs,
+		Paths:        map[string]*SwaggerPath{},
+		Extensions:   doc.Extensions,
+	}
+	if s.Info != nil && s.Info.Summary != "" {
+		c.warn("/info/summary", "the info summary is not supported by Swagger 2.0")
+		s.Info.Summary = ""
+	}
+	if len(doc.Webhooks) > 0 {
+		c.warn("/webhooks", "webhooks are not supported by Swagger 2.0")
+	}
+	s.Host, s.BasePath, s.Schemes = c.servers("/servers", doc.Servers)
+	c.components(s)
+	s.Security = c.security("/security", doc.Security)
+
+	for _, name := range sortedKeys(doc.Paths) {
+		s.Paths[name] = c.path("/paths/"+pointerEscape(name), doc.Paths[name])
+	}
+	// MIME types shared by every operation move to the document.
+	s.Consumes = hoist(s, func(o *SwaggerOperation) *[]string { return &o.Consumes })
+	s.Produces = hoist(s, func(o *SwaggerOperation) *[]string { return &o.Produces })
+	return s, c.warnings
+}
+
+// schemas rewrites the references of every schema and reports the keywords Swagger 2.0 lacks.
+func (c *openAPIConverter) schemas() {
+	Walk(c.doc, Visitor{Enter: func(cursor *Cursor) WalkAction {
+		s, ok := cursor.Value.(*Schema)
+		if !ok {
+			return WalkContinue
+		}
+		pointer := cursor.Pointer
+		if ref, ok := parseComponentRef(s.Ref); ok && ref.Kind == "schemas" {
+			s.Ref = "#/definitions/" + pointerEscape(ref.Name)
+		} else if s.Ref != "" && strings.HasPrefix(s.Ref, "#/") {
+			c.warn(pointer+"/$ref", "reference '%s' has no Swagger 2.0 equivalent", s.Ref)
+		}
+		if s.Bool != nil {
+			c.warn(pointer, "boolean schemas are not supported by Swagger 2.0")
+			s.Bool = nil
+		}
+		if len(s.Type) > 1 {
+			types := Types{}
+			for _, t := range s.Type {
+				if t == "null" {
+					s.Nullable = true
+				} else {
+					types = append(types, t)
+				}
+			}
+			if len(types) > 1 {
+				c.warn(pointer+"/type", "multiple types are not supported by Swagger 2.0; using '%s'", types[0])
+			}
+			s.Type = types[:1]
+		}
+		if s.Nullable {
+			if s.Extensions == nil {
+				s.Extensions = Extensions{}
+			}
+			s.Extensions["x-nullable"] = true
+			s.Nullable = false
+		}
+		if v, ok := s.ExclusiveMaximum.limit(); ok {
+			s.Maximum, s.ExclusiveMaximum = &v, &ExclusiveBound{Exclusive: true}
+		}
+		if v, ok := s.ExclusiveMinimum.limit(); ok {
+			s.Minimum, s.ExclusiveMinimum = &v, &ExclusiveBound{Exclusive: true}
+		}
+		if s.Const != nil {
+			s.Enum, s.Const = []interface{}{s.Const}, nil
+		}
+		if len(s.Examples) > 0 {
+			if s.Example == nil {
+				s.Example = s.Examples[0]
+			}
+			s.Examples = nil
+		}
+		if d := s.Discriminator; d != nil {
+			if len(d.Mapping) > 0 {
+				c.warn(pointer+"/discriminator/mapping", "Swagger 2.0 discriminators have no mapping; values must be definition names")
+			}
+			s.Discriminator = &Discriminator{PropertyName: d.PropertyName, propertyOnly: true}
+		}
+		if s.WriteOnly {
+			c.warn(pointer+"/writeOnly", "writeOnly is not supported by Swagger 2.0")
+			s.WriteOnly = false
+		}
+		if s.Deprecated {
+			c.warn(pointer+"/deprecated", "deprecated schemas are not supported by Swagger 2.0")
+			s.Deprecated = false
+		}
+		c.drop(pointer, "oneOf", &s.OneOf)
+		c.drop(pointer, "anyOf", &s.AnyOf)
+		c.drop(pointer, "not", &s.Not)
+		c.drop(pointer, "if", &s.If)
+		c.drop(pointer, "then", &s.Then)
+		c.drop(pointer, "else", &s.Else)
+		c.drop(pointer, "dependentSchemas", &s.DependentSchemas)
+		c.drop(pointer, "dependentRequired", &s.DependentRequired)
+		c.drop(pointer, "prefixItems", &s.PrefixItems)
+		c.drop(pointer, "contains", &s.Contains)
+		c.drop(pointer, "minContains", &s.MinContains)
+		c.drop(pointer, "maxContains", &s.MaxContains)
+		c.drop(pointer, "unevaluatedItems", &s.UnevaluatedItems)
+		c.drop(pointer, "patternProperties", &s.PatternProperties)
+		c.drop(pointer, "propertyNames", &s.PropertyNames)
+		c.drop(pointer, "unevaluatedProperties", &s.UnevaluatedProperties)
+		c.drop(pointer, "contentEncoding", &s.ContentEncoding)
+		c.drop(pointer, "contentMediaType", &s.ContentMediaType)
+		c.drop(pointer, "contentSchema", &s.ContentSchema)
+		c.drop(pointer, "$id", &s.ID)
+		c.drop(pointer, "$schema", &s.Dialect)
+		c.drop(pointer, "$anchor", &s.Anchor)
+		c.drop(pointer, "$comment", &s.Comment)
+		c.drop(pointer, "$defs", &s.Defs)
+		return WalkContinue
+	}})
+}
+
+// drop clears a schema keyword that Swagger 2.0 does not support and reports it when it was set.
+func (c *openAPIConverter) drop(pointer, keyword string, field interface{}) {
+	v := reflect.ValueOf(field).Elem()
+	if !v.IsZero() {
+		c.warn(pointer+"/"+keyword, "%s is not supported by Swagger 2.0 and was dropped", keyword)
+		v.SetZero()
+	}
+}
+
+// servers collapses the Server objects into a host, base path and schemes.
+func (c *openAPIConverter) servers(pointer string, servers []*Server) (host, basePath string, schemes []string) {
+	for i, server := range servers {
+		u, err := url.Parse(server.expandDefaults())
+		if err != nil {
+			c.warn(fmt.Sprintf("%s/%d/url", pointer, i), "server URL cannot be parsed: %s", err)
+			continue
+		}
+		if i == 0 {
+			host, basePath = u.Host, strings.TrimSuffix(u.Path, "/")
+		} else if u.Host != host || strings.TrimSuffix(u.Path, "/") != basePath {
+			c.warn(fmt.Sprintf("%s/%d", pointer, i), "Swagger 2.0 supports a single host and base path; server '%s' was dropped", server.URL)
+			continue
+		}
+		if u.Scheme != "" && !contains(schemes, u.Scheme) {
+			schemes = append(schemes, u.Scheme)
+		}
+	}
+	return host, basePath, schemes
+}
+
+// expandDefaults returns the URL of the server with every variable replaced by its default value.
+func (s *Server) expandDefaults() string {
+	u := s.URL
+	for name, variable := range s.Variables {
+		u = strings.ReplaceAll(u, "{"+name+"}", variable.Default)
+	}
+	return u
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// components converts the reusable objects of the document.
+func (c *openAPIConverter) components(s *Swagger) {
+	components := c.doc.Components
+	if components == nil {
+		return
+	}
+	s.Definitions = components.Schemas
+	for _, name := range sortedKeys(components.Parameters) {
+		pointer := "/components/parameters/" + pointerEscape(name)
+		if p := c.parameter(pointer, components.Parameters[name]); p != nil {
+			if s.Parameters == nil {
+				s.Parameters = map[string]*SwaggerParameter{}
+			}
+			s.Parameters[name] = p
+		}
+	}
+	for _, name := range sortedKeys(components.RequestBodies) {
+		pointer := "/components/requestBodies/" + pointerEscape(name)
+		params, _ := c.requestBody(pointer, components.RequestBodies[name])
+		if len(params) != 1 || params[0].In != "body" {
+			c.warn(pointer, "form request bodies cannot be shared in Swagger 2.0; they are inlined where referenced")
+			continue
+		}
+		key := name
+		if _, ok := s.Parameters[key]; ok {
+			key = name + "Body"
+			c.warn(pointer, "a parameter is also named '%s'; the request body is exported as '%s'", name, key)
+		}
+		if s.Parameters == nil {
+			s.Parameters = map[string]*SwaggerParameter{}
+		}
+		s.Parameters[key] = params[0]
+		c.bodyNames[name] = key
+	}
+	for _, name := range sortedKeys(components.Responses) {
+		if s.Responses == nil {
+			s.Responses = map[string]*SwaggerResponse{}
+		}
+		s.Responses[name], _ = c.response("/components/responses/"+pointerEscape(name), components.Responses[name])
+	}
+	for _, name := range sortedKeys(components.SecuritySchemes) {
+		pointer := "/components/securitySchemes/" + pointerEscape(name)
+		if scheme := c.securityScheme(pointer, components.SecuritySchemes[name]); scheme != nil {
+			if s.SecurityDefinitions == nil {
+				s.SecurityDefinitions = map[string]*SwaggerSecurityScheme{}
+			}
+			s.SecurityDefinitions[name] = scheme
+		} else {
+			c.dropped[name] = true
+		}
+	}
+	for _, name := range sortedKeys(components.Headers) {
+		c.warn("/components/headers/"+pointerEscape(name), "reusable headers are not supported by Swagger 2.0; they are inlined where referenced")
+	}
+	for _, name := range sortedKeys(components.Examples) {
+		c.warn("/components/examples/"+pointerEscape(name), "reusable examples are not supported by Swagger 2.0; they are inlined where referenced")
+	}
+	if len(components.Links) > 0 {
+		c.warn("/components/links", "links are not supported by Swagger 2.0")
+	}
+	if len(components.Callbacks) > 0 {
+		c.warn("/components/callbacks", "callbacks are not supported by Swagger 2.0")
+	}
+	if len(components.PathItems) > 0 {
+		c.warn("/components/pathItems", "reusable path items are not supported by Swagger 2.0")
+	}
+}
+
+func (c *openAPIConverter) path(pointer string, p *Path) *SwaggerPath {
+	if p == nil {
+		return nil
+	}
+	if p.Ref != "" {
+		c.warn(pointer, "path item references are kept as they are and not converted")
+	}
+	path := &SwaggerPath{Ref: p.Ref, Extensions: p.Extensions}
+	if p.Summary != "" || p.Description != "" {
+		c.warn(pointer, "the summary and description of path items are not supported by Swagger 2.0")
+	}
+	if len(p.Servers) > 0 {
+		c.warn(pointer+"/servers", "path level servers are not supported by Swagger 2.0")
+	}
+	if p.Trace != nil {
+		c.warn(pointer+"/trace", "TRACE operations are not supported by Swagger 2.0")
+	}
+	for i, param := range p.Parameters {
+		if converted := c.parameter(fmt.Sprintf("%s/parameters/%d", pointer, i), param); converted != nil {
+			path.Parameters = append(path.Parameters, converted)
+		}
+	}
+	path.Get = c.operation(pointer+"/get", p.Get)
+	path.Put = c.operation(pointer+"/put", p.Put)
+	path.Post = c.operation(pointer+"/post", p.Post)
+	path.Delete = c.operation(pointer+"/delete", p.Delete)
+	path.Options = c.operation(pointer+"/options", p.Options)
+	path.Head = c.operation(pointer+"/head", p.Head)
+	path.Patch = c.operation(pointer+"/patch", p.Patch)
+	return path
+}
+
+func (c *openAPIConverter) operation(pointer string, o *Operation) *SwaggerOperation {
+	if o == nil {
+		return nil
+	}
+	op := &SwaggerOperation{
+		Tags:         o.Tags,
+		Summary:      o.Summary,
+		Description:  o.Description,
+		ExternalDocs: o.ExternalDocs,
+		OperationID:  o.OperationID,
+		Deprecated:   o.Deprecated,
+		Security:     c.security(pointer+"/security", o.Security),
+		Responses:    map[string]*SwaggerResponse{},
+		Extensions:   o.Extensions,
+	}
+	if len(o.Servers) > 0 {
+		host, basePath, schemes := c.servers(pointer+"/servers", o.Servers)
+		if host != "" && (host != c.doc.serverHost() || basePath != c.doc.serverBasePath()) {
+			c.warn(pointer+"/servers", "operation servers with a different host or base path are not supported by Swagger 2.0")
+		}
+		op.Schemes = schemes
+	}
+	if len(o.Callbacks) > 0 {
+		c.warn(pointer+"/callbacks", "callbacks are not supported by Swagger 2.0")
+	}
+	for i, param := range o.Parameters {
+		if converted := c.parameter(fmt.Sprintf("%s/parameters/%d", pointer, i), param); converted != nil {
+			op.Parameters = append(op.Parameters, converted)
+		}
+	}
+	if o.RequestBody != nil {
+		params, mediaTypes := c.requestBody(pointer+"/requestBody", o.RequestBody)
+		op.Parameters = append(op.Parameters, params...)
+		op.Consumes = mediaTypes
+	}
+	for _, code := range sortedKeys(o.Responses) {
+		response, mediaTypes := c.response(pointer+"/responses/"+pointerEscape(code), o.Responses[code])
+		op.Responses[code] = response
+		for _, mediaType := range mediaTypes {
+			if !contains(op.Produces, mediaType) {
+				op.Produces = append(op.Produces, mediaType)
+			}
+		}
+	}
+	return op
+}
+
+// serverHost and serverBasePath return the host and base path of the first document server.
+func (o *OpenAPI) serverHost() string {
+	if len(o.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(o.Servers[0].expandDefaults())
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+func (o *OpenAPI) serverBasePath() string {
+	if len(o.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(o.Servers[0].expandDefaults())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// hoist moves the MIME types that every operation lists to the document.
+func hoist(s *Swagger, field func(*SwaggerOperation) *[]string) []string {
+	var ops []*SwaggerOperation
+	for _, path := range s.Paths {
+		if path == nil {
+			continue
+		}
+		for _, op := range []*SwaggerOperation{path.Get, path.Put, path.Post, path.Delete, path.Options, path.Head, path.Patch} {
+			if op != nil && len(*field(op)) > 0 {
+				ops = append(ops, op)
+			}
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	first := *field(ops[0])
+	for _, op := range ops[1:] {
+		if strings.Join(*field(op), ",") != strings.Join(first, ",") {
+			return nil
+		}
+	}
+	for _, op := range ops {
+		*field(op) = nil
+	}
+	return first
+}
+
+// parameter converts a query, header or path parameter. Cookie parameters cannot be converted.
+func (c *openAPIConverter) parameter(pointer string, p *Parameter) *SwaggerParameter {
+	if p == nil {
+		return nil
+	}
+	if p.Ref != "" {
+		if ref, ok := parseComponentRef(p.Ref); ok && ref.Kind == "parameters" {
+			if target, ok := c.doc.lookupRef(p.Ref).(*Parameter); ok && target.In == "cookie" {
+				c.warn(pointer, "cookie parameters are not supported by Swagger 2.0")
+				return nil
+			}
+			return &SwaggerParameter{Ref: "#/parameters/" + pointerEscape(ref.Name)}
+		}
+		c.warn(pointer+"/$ref", "reference '%s' has no Swagger 2.0 equivalent", p.Ref)
+		return nil
+	}
+	if p.In == "cookie" {
+		c.warn(pointer, "cookie parameters are not supported by Swagger 2.0")
+		return nil
+	}
+	param := &SwaggerParameter{
+		Name:            p.Name,
+		In:              p.In,
+		Description:     p.Description,
+		Required:        p.Required,
+		AllowEmptyValue: p.AllowEmptyValue,
+		Extensions:      p.Extensions,
+	}
+	schema := p.Schema
+	if schema == nil && len(p.Content) > 0 {
+		c.warn(pointer+"/content", "parameters described by content are not supported by Swagger 2.0; using the schema of the first media type")
+		schema = p.Content[sortedKeys(p.Content)[0]].Schema
+	}
+	param.SwaggerItems = c.items(pointer+"/schema", schema)
+	if param.Type == "array" {
+		param.CollectionFormat = c.collectionFormat(pointer, p.In, p.Style, p.IsExploded())
+	}
+	if p.Deprecated {
+		c.warn(pointer+"/deprecated", "deprecated parameters are not supported by Swagger 2.0")
+	}
+	if p.Example != nil || len(p.Examples) > 0 {
+		c.warn(pointer, "parameter examples are not supported by Swagger 2.0")
+	}
+	return param
+}
+
+// collectionFormat maps a parameter style and explode flag to a collectionFormat.
+func (c *openAPIConverter) collectionFormat(pointer, in, style string, exploded bool) string {
+	if style == "" {
+		style = "simple"
+		if in == "query" || in == "formData" {
+			style = "form"
+		}
+	}
+	switch {
+	case style == "form" && exploded:
+		return "multi"
+	case style == "form", style == "simple":
+		return "csv"
+	case style == "spaceDelimited" && !exploded:
+		return "ssv"
+	case style == "pipeDelimited" && !exploded:
+		return "pipes"
+	}
+	c.warn(pointer+"/style", "style '%s' is not supported by Swagger 2.0; using csv", style)
+	return "csv"
+}
+
+// items converts the schema of a non-body parameter or header. Swagger 2.0 only allows
+// primitive types and arrays of them there.
+func (c *openAPIConverter) items(pointer string, s *Schema) SwaggerItems {
+	s = c.definition(s)
+	if s == nil {
+		c.warn(pointer, "the value has no schema; assuming string")
+		return SwaggerItems{Type: "string"}
+	}
+	items := SwaggerItems{
+		Format:      s.Format,
+		Default:     s.Default,
+		Maximum:     s.Maximum,
+		Minimum:     s.Minimum,
+		MaxLength:   s.MaxLength,
+		MinLength:   s.MinLength,
+		Pattern:     s.Pattern,
+		MaxItems:    s.MaxItems,
+		MinItems:    s.MinItems,
+		UniqueItems: s.UniqueItems,
+		Enum:        s.Enum,
+		MultipleOf:  s.MultipleOf,
+	}
+	items.ExclusiveMaximum = s.ExclusiveMaximum.exclusive()
+	items.ExclusiveMinimum = s.ExclusiveMinimum.exclusive()
+	switch t := s.Type.String(); t {
+	case "string", "number", "integer", "boolean":
+		items.Type = t
+		if t == "string" && s.Format == "binary" {
+			items.Type, items.Format = "file", ""
+		}
+	case "array":
+		items.Type = "array"
+		if s.Items != nil {
+			i := c.items(pointer+"/items", s.Items)
+			items.Items = &i
+		}
+	default:
+		c.warn(pointer, "only primitive types and arrays can be used outside a body in Swagger 2.0; using string")
+		items.Type = "string"
+	}
+	return items
+}
+
+// definition returns the schema a "#/definitions/" reference points to, or s itself.
+func (c *openAPIConverter) definition(s *Schema) *Schema {
+	if s == nil || c.doc.Components == nil {
+		return s
+	}
+	if name, ok := strings.CutPrefix(s.Ref, "#/definitions/"); ok {
+		if target := c.doc.Components.Schemas[pointerUnescape(name)]; target != nil {
+			return target
+		}
+	}
+	return s
+}
+
+// requestBody converts a request body to a body parameter or to formData parameters, and
+// returns them with the media types the body can be sent as.
+func (c *openAPIConverter) requestBody(pointer string, rb *RequestBody) ([]*SwaggerParameter, []string) {
+	if rb.Ref != "" {
+		ref, ok := parseComponentRef(rb.Ref)
+		target, _ := c.doc.lookupRef(rb.Ref).(*RequestBody)
+		if !ok || target == nil {
+			c.warn(pointer+"/$ref", "reference '%s' cannot be resolved", rb.Ref)
+			return nil, nil
+		}
+		if name, ok := c.bodyNames[ref.Name]; ok {
+			return []*SwaggerParameter{{Ref: "#/parameters/" + pointerEscape(name)}}, sortedKeys(target.Content)
+		}
+		return c.requestBody(ref.Pointer(), target)
+	}
+
+	mediaTypes := sortedKeys(rb.Content)
+	if len(mediaTypes) == 0 {
+		return nil, nil
+	}
+	var form, body []string
+	for _, mediaType := range mediaTypes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			form = append(form, mediaType)
+		} else {
+			body = append(body, mediaType)
+		}
+	}
+	if len(form) > 0 && len(body) > 0 {
+		c.warn(pointer+"/content", "Swagger 2.0 cannot describe both form and body content; only the form media types are exported")
+	}
+	if len(form) > 0 {
+		return c.formParameters(pointer+"/content/"+pointerEscape(form[0]), rb, rb.Content[form[0]]), form
+	}
+
+	content := rb.Content[body[0]]
+	for _, mediaType := range body[1:] {
+		if !sameSchema(content.Schema, rb.Content[mediaType].Schema) {
+			c.warn(pointer+"/content/"+pointerEscape(mediaType), "Swagger 2.0 allows a single body schema; the schema of '%s' is used", body[0])
+		}
+	}
+	param := &SwaggerParameter{
+		Name:        "body",
+		In:          "body",
+		Description: rb.Description,
+		Required:    rb.Required,
+		Schema:      content.Schema,
+		Extensions:  rb.Extensions,
+	}
+	if param.Schema == nil {
+		param.Schema = &Schema{}
+	}
+	return []*SwaggerParameter{param}, body
+}
+
+// sameSchema reports whether two media types share a schema, either the same object or the same $ref.
+func sameSchema(a, b *Schema) bool {
+	return a == b || (a != nil && b != nil && a.Ref != "" && a.Ref == b.Ref)
+}
+
+// formParameters converts the properties of a form body to formData parameters.
+func (c *openAPIConverter) formParameters(pointer string, rb *RequestBody, m *MediaType) []*SwaggerParameter {
+	schema := c.definition(m.Schema)
+	if schema == nil || len(schema.Properties) == 0 {
+		c.warn(pointer+"/schema", "form bodies need an object schema with properties to be converted")
+		return nil
+	}
+	var params []*SwaggerParameter
+	for _, name := range sortedKeys(schema.Properties) {
+		property := schema.Properties[name]
+		propertyPointer := pointer + "/schema/properties/" + pointerEscape(name)
+		param := &SwaggerParameter{
+			Name:         name,
+			In:           "formData",
+			Required:     contains(schema.Required, name),
+			SwaggerItems: c.items(propertyPointer, property),
+		}
+		if property != nil {
+			param.Description = property.Description
+		}
+		if param.Type == "array" {
+			style, exploded := "", true
+			if e := m.Encoding[name]; e != nil {
+				style, exploded = e.Style, e.IsExploded()
+			}
+			param.CollectionFormat = c.collectionFormat(pointer+"/encoding/"+pointerEscape(name), "formData", style, exploded)
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// response converts a response and returns the media types it can be sent as.
+func (c *openAPIConverter) response(pointer string, r *Response) (*SwaggerResponse, []string) {
+	if r == nil {
+		return nil, nil
+	}
+	if r.Ref != "" {
+		if ref, ok := parseComponentRef(r.Ref); ok && ref.Kind == "responses" {
+			var mediaTypes []string
+			if target, ok := c.doc.lookupRef(r.Ref).(*Response); ok {
+				mediaTypes = sortedKeys(target.Content)
+			}
+			return &SwaggerResponse{Ref: "#/responses/" + pointerEscape(ref.Name)}, mediaTypes
+		}
+		c.warn(pointer+"/$ref", "reference '%s' has no Swagger 2.0 equivalent", r.Ref)
+		return &SwaggerResponse{Description: r.Description}, nil
+	}
+	response := &SwaggerResponse{Description: r.Description, Extensions: r.Extensions}
+	for _, name := range sortedKeys(r.Headers) {
+		h := r.Headers[name]
+		if h == nil {
+			continue
+		}
+		headerPointer := pointer + "/headers/" + pointerEscape(name)
+		if h.Ref != "" {
+			target, ok := c.doc.lookupRef(h.Ref).(*Header)
+			if !ok {
+				c.warn(headerPointer+"/$ref", "reference '%s' cannot be resolved", h.Ref)
+				continue
+			}
+			h = target
+		}
+		if len(h.Content) > 0 {
+			c.warn(headerPointer+"/content", "header content is not supported by Swagger 2.0")
+		}
+		if response.Headers == nil {
+			response.Headers = map[string]*SwaggerHeader{}
+		}
+		response.Headers[name] = &SwaggerHeader{Description: h.Description, SwaggerItems: c.items(headerPointer+"/schema", h.Schema)}
+	}
+	if len(r.Links) > 0 {
+		c.warn(pointer+"/links", "links are not supported by Swagger 2.0")
+	}
+
+	mediaTypes := sortedKeys(r.Content)
+	for i, mediaType := range mediaTypes {
+		m := r.Content[mediaType]
+		mediaPointer := pointer + "/content/" + pointerEscape(mediaType)
+		if i == 0 {
+			response.Schema = m.Schema
+		} else if !sameSchema(response.Schema, m.Schema) {
+			c.warn(mediaPointer, "Swagger 2.0 allows a single response schema; the schema of '%s' is used", mediaTypes[0])
+		}
+		example := m.Example
+		if example == nil && len(m.Examples) > 0 {
+			c.warn(mediaPointer+"/examples", "Swagger 2.0 allows a single example per MIME type; the first one is used")
+			first := m.Examples[sortedKeys(m.Examples)[0]]
+			if first.Ref != "" {
+				first, _ = c.doc.lookupRef(first.Ref).(*Example)
+			}
+			if first != nil {
+				example = first.Value
+			}
+		}
+		if example != nil {
+			if response.Examples == nil {
+				response.Examples = map[string]interface{}{}
+			}
+			response.Examples[mediaType] = example
+		}
+	}
+	return response, mediaTypes
+}
+
+// security drops the requirements that use a security scheme Swagger 2.0 cannot represent.
+func (c *openAPIConverter) security(pointer string, requirements []*SecurityRequirement) []*SecurityRequirement {
+	if requirements == nil {
+		return nil
+	}
+	kept := []*SecurityRequirement{}
+	for i, requirement := range requirements {
+		if requirement != nil {
+			if name, ok := c.droppedScheme(*requirement); ok {
+				c.warn(fmt.Sprintf("%s/%d", pointer, i), "the requirement uses security scheme '%s', which is not supported by Swagger 2.0, and was dropped", name)
+				continue
+			}
+		}
+		kept = append(kept, requirement)
+	}
+	if len(kept) == 0 && len(requirements) > 0 {
+		c.warn(pointer, "no security requirement is left; the requirements of the enclosing scope apply instead")
+	}
+	return kept
+}
+
+// droppedScheme returns the first scheme of requirement that has no Swagger 2.0 equivalent.
+func (c *openAPIConverter) droppedScheme(requirement SecurityRequirement) (string, bool) {
+	for _, name := range sortedKeys(requirement) {
+		if c.dropped[name] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (c *openAPIConverter) securityScheme(pointer string, s *SecurityScheme) *SwaggerSecurityScheme {
+	if s.Ref != "" {
+		c.warn(pointer+"/$ref", "security scheme references are not supported by Swagger 2.0")
+		return nil
+	}
+	scheme := &SwaggerSecurityScheme{Description: s.Description, Extensions: s.Extensions}
+	switch {
+	case s.Type == "http" && strings.EqualFold(s.Scheme, "basic"):
+		scheme.Type = "basic"
+	case s.Type == "http" && strings.EqualFold(s.Scheme, "bearer"):
+		c.warn(pointer, "bearer authentication is exported as an apiKey in the Authorization header")
+		scheme.Type, scheme.Name, scheme.In = "apiKey", "Authorization", "header"
+	case s.Type == "apiKey" && s.In != "cookie":
+		scheme.Type, scheme.Name, scheme.In = "apiKey", s.Name, s.In
+	case s.Type == "oauth2" && s.Flows != nil:
+		scheme.Type = "oauth2"
+		flows := []struct {
+			name string
+			flow *OAuthFlow
+		}{
+			{"implicit", s.Flows.Implicit},
+			{"password", s.Flows.Password},
+			{"application", s.Flows.ClientCredentials},
+			{"accessCode", s.Flows.AuthorizationCode},
+		}
+		for _, f := range flows {
+			if f.flow == nil {
+				continue
+			}
+			if scheme.Flow != "" {
+				c.warn(pointer+"/flows", "Swagger 2.0 allows a single OAuth2 flow per scheme; only '%s' is exported", scheme.Flow)
+				break
+			}
+			scheme.Flow = f.name
+			scheme.AuthorizationURL, scheme.TokenURL, scheme.Scopes = f.flow.AuthorizationURL, f.flow.TokenURL, f.flow.Scopes
+		}
+	default:
+		c.warn(pointer, "security scheme type '%s' is not supported by Swagger 2.0", s.Type)
+		return nil
+	}
+	return scheme
+}
@@ -0,0 +1,119 @@
+package oas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpenAPIToSwaggerWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		openAPI  string
+		warnings []string // The pointers of the expected warnings, in order.
+	}{
+		{
+			name:    "no warnings",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "servers": [{"url": "https://api.example.com/v1"}], "paths": {}}`,
+		},
+		{
+			name:     "info summary",
+			openAPI:  `{"openapi": "3.1.0", "info": {"title": "t", "summary": "s", "version": "1"}, "paths": {}}`,
+			warnings: []string{"/info/summary"},
+		},
+		{
+			name:     "webhooks",
+			openAPI:  `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "webhooks": {"ping": {}}}`,
+			warnings: []string{"/webhooks"},
+		},
+		{
+			name: "multiple servers",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+				"servers": [{"url": "https://api.example.com/v1"}, {"url": "https://backup.example.com/v1"}]}`,
+			warnings: []string{"/servers/1"},
+		},
+		{
+			name: "oneOf",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+				"components": {"schemas": {"Id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}}}}`,
+			warnings: []string{"/components/schemas/Id/oneOf"},
+		},
+		{
+			name: "discriminator mapping",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+				"components": {"schemas": {"Pet": {"type": "object", "discriminator": {"propertyName": "kind", "mapping": {"dog": "Dog"}}}}}}`,
+			warnings: []string{"/components/schemas/Pet/discriminator/mapping"},
+		},
+		{
+			name: "cookie parameter",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {
+				"parameters": [{"name": "session", "in": "cookie", "schema": {"type": "string"}}],
+				"responses": {"200": {"description": "ok"}}}}}}`,
+			warnings: []string{"/paths/~1pets/get/parameters/0"},
+		},
+		{
+			name: "reusable header",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {},
+				"components": {"headers": {"Rate-Limit": {"schema": {"type": "integer"}}}}}`,
+			warnings: []string{"/components/headers/Rate-Limit"},
+		},
+		{
+			name: "requirement of an unsupported scheme",
+			openAPI: `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "paths": {},
+				"security": [{"tls": []}],
+				"components": {"securitySchemes": {"tls": {"type": "mutualTLS"}}}}`,
+			warnings: []string{"/components/securitySchemes/tls", "/security/0", "/security"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			openAPI, err := LoadJSON([]byte(test.openAPI))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, warnings := openAPI.ToSwagger()
+			if got := warningPointers(warnings); !reflect.DeepEqual(got, test.warnings) {
+				t.Errorf("warnings = %v, want pointers %v", warnings, test.warnings)
+			}
+		})
+	}
+}
+
+func TestOpenAPIRoundTrip(t *testing.T) {
+	openAPI, err := LoadJSON([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Pets", "version": "1"},
+		"servers": [{"url": "https://api.example.com/v1"}],
+		"paths": {"/pets": {"post": {
+			"operationId": "addPet",
+			"requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+			"responses": {"201": {"description": "created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}
+		}}},
+		"components": {"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string"}}}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger, warnings := openAPI.ToSwagger()
+	if len(warnings) > 0 {
+		t.Fatalf("ToSwagger() warnings = %v, want none", warnings)
+	}
+	if swagger.Host != "api.example.com" || swagger.BasePath != "/v1" {
+		t.Errorf("host and base path = %s%s, want api.example.com/v1", swagger.Host, swagger.BasePath)
+	}
+
+	back, warnings := swagger.ToOpenAPI()
+	// The body parameter needs a name in Swagger 2.0, which OpenAPI 3.0 cannot keep.
+	if got, want := warningPointers(warnings), []string{"/paths/~1pets/post/parameters/0/name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToOpenAPI() warnings = %v, want pointers %v", warnings, want)
+	}
+	if len(back.Servers) != 1 || back.Servers[0].URL != "https://api.example.com/v1" {
+		t.Errorf("servers = %v, want https://api.example.com/v1", back.Servers)
+	}
+	op := back.Paths["/pets"].Post
+	if op.RequestBody == nil || !op.RequestBody.Required || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/Pet" {
+		t.Errorf("request body = %+v, want a required body of #/components/schemas/Pet", op.RequestBody)
+	}
+	if back.Components.Schemas["Pet"] == nil {
+		t.Error("schema Pet was lost")
+	}
+}
@@ -1,5 +1,7 @@
 package oas
 
+import "strconv"
+
 // UnmarshalError is an error type for unmarshalling errors.
 // Choose the error based on input type(JSON or YAML) you passed.
 type UnmarshalError struct {
@@ -11,9 +13,40 @@ func (e *UnmarshalError) Error() string {
 	return "error marshalling to JSON: " + e.JSONErr.Error() + ", error marshalling to YAML: " + e.YAMLErr.Error()
 }
 
+// ParseError is returned when a document cannot be parsed or, in strict mode, contains an unknown field.
+type ParseError struct {
+	Position Position // The location of the error. Line is zero when the parser did not report one.
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	if e.Position.IsValid() || e.Position.File != "" {
+		return e.Position.String() + ": " + e.Err.Error()
+	}
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 type ValidationError struct {
-	Err   error
-	Field string
+	Err       error
+	Field     string
+	Position  Position // The source position of the schema that rejected the value, when known.
+	Offset    int64    // The byte offset of the value in the JSON input, when HasOffset is set.
+	HasOffset bool     // Set when the value was read from JSON input.
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Err.Error()
+	}
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
 }
 
 type ValidationErrors []ValidationError
@@ -21,7 +54,14 @@ type ValidationErrors []ValidationError
 func (e ValidationErrors) Error() string {
 	var err string
 	for _, v := range e {
-		err += v.Field + ": " + v.Err.Error() + "\n"
+		if v.Position.IsValid() {
+			err += v.Position.String() + ": "
+		}
+		err += v.Error()
+		if v.HasOffset {
+			err += " (offset " + strconv.FormatInt(v.Offset, 10) + ")"
+		}
+		err += "\n"
 	}
 	return err
 }
@@ -2,9 +2,10 @@ package oas
 
 // Example represents an example object in OpenAPI
 type Example struct {
-	Summary       string      `json:"summary,omitempty" yaml:"summary"`
-	Description   string      `json:"description,omitempty" yaml:"description"`
-	Value         interface{} `json:"value,omitempty" yaml:"value"`
-	ExternalValue string      `json:"externalValue,omitempty" yaml:"externalValue"`
+	Summary       string      `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description   string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Value         interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	ExternalValue string      `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
 	Ref           string      `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Extensions    Extensions  `json:"-" yaml:"-"`
 }
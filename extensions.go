@@ -0,0 +1,780 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Extensions holds the specification extensions of an object: the properties whose names start with "x-".
+type Extensions map[string]interface{}
+
+// isExtension reports whether key names a specification extension.
+func isExtension(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
+
+// Get returns the value of the extension named key, e.g. "x-internal".
+func (e Extensions) Get(key string) (interface{}, bool) {
+	v, ok := e[key]
+	return v, ok
+}
+
+// String returns the extension named key if it is a string.
+func (e Extensions) String(key string) (string, bool) {
+	v, ok := e[key].(string)
+	return v, ok
+}
+
+// Bool returns the extension named key if it is a boolean.
+func (e Extensions) Bool(key string) (bool, bool) {
+	v, ok := e[key].(bool)
+	return v, ok
+}
+
+// Int returns the extension named key if it is a whole number.
+func (e Extensions) Int(key string) (int64, bool) {
+	switch v := e[key].(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int64(v), true
+		}
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// Float returns the extension named key if it is a number.
+func (e Extensions) Float(key string) (float64, bool) {
+	switch v := e[key].(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Decode decodes the extension named key into v, which must be a pointer, using its JSON representation.
+func (e Extensions) Decode(key string, v interface{}) error {
+	value, ok := e[key]
+	if !ok {
+		return fmt.Errorf("extension '%s' is not set", key)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// unmarshalJSONObject decodes data into v, a pointer to a struct with an Extensions field, and
+// sets the extensions found in data. data is split into its properties once and each field is
+// decoded from its own property, so an object is not scanned twice at every level of the document.
+func unmarshalJSONObject(data []byte, v interface{}) error {
+	_, err := unmarshalJSONObjectFields(data, v)
+	return err
+}
+
+// unmarshalJSONObjectFields is unmarshalJSONObject that also returns the properties of data.
+func unmarshalJSONObjectFields(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	extensions, raw, err := unmarshalJSONFields(data, v)
+	reflect.ValueOf(v).Elem().FieldByName("Extensions").Set(reflect.ValueOf(extensions))
+	return raw, err
+}
+
+// unmarshalJSONFields decodes data into v, a pointer to a struct, and returns the extensions
+// and the properties found in data.
+func unmarshalJSONFields(data []byte, v interface{}) (Extensions, map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, err
+	}
+	value := reflect.ValueOf(v).Elem()
+	fields := jsonFields(value.Type())
+	var extensions Extensions
+	for key, property := range raw {
+		if isExtension(key) {
+			var decoded interface{}
+			if err := json.Unmarshal(property, &decoded); err != nil {
+				return nil, nil, err
+			}
+			if extensions == nil {
+				extensions = Extensions{}
+			}
+			extensions[key] = decoded
+			continue
+		}
+		field, ok := fieldNamed(fields, key)
+		if !ok {
+			continue
+		}
+		target := value.FieldByIndex(field.index)
+		if err := json.Unmarshal(property, target.Addr().Interface()); err != nil && !setIntegral(target, property) {
+			return nil, nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return extensions, raw, nil
+}
+
+// setIntegral sets an integer or pointer to integer target from a number with a zero fractional
+// part, such as 2.0, which JSON Schema treats as an integer but encoding/json does not.
+func setIntegral(target reflect.Value, property json.RawMessage) bool {
+	var f float64
+	if err := json.Unmarshal(property, &f); err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return false
+	}
+	t := target.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return false
+	}
+	if reflect.Zero(t).OverflowInt(int64(f)) {
+		return false
+	}
+	if target.Kind() == reflect.Pointer {
+		target.Set(reflect.New(t))
+		target = target.Elem()
+	}
+	target.SetInt(int64(f))
+	return true
+}
+
+// fieldNamed returns the field a JSON property sets: the one with the same name or,
+// as encoding/json does, the first whose name matches ignoring case.
+func fieldNamed(fields []jsonField, key string) (jsonField, bool) {
+	for _, field := range fields {
+		if field.name == key {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, key) {
+			return field, true
+		}
+	}
+	return jsonField{}, false
+}
+
+// marshalJSONObject encodes v, a struct with an Extensions field, followed by its extensions.
+// The fields whose JSON names are in explicit are written even when they are empty.
+func marshalJSONObject(v interface{}, explicit ...string) ([]byte, error) {
+	value := reflect.ValueOf(v)
+	data, err := json.Marshal(toPlain(value, explicit).Interface())
+	extensions := value.FieldByName("Extensions").Interface().(Extensions)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	empty := buf.Len() == 1
+	for _, key := range sortedKeys(extensions) {
+		if !isExtension(key) {
+			continue
+		}
+		value, err := json.Marshal(extensions[key])
+		if err != nil {
+			return nil, err
+		}
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalYAMLObject decodes node into v, a pointer to a struct with an Extensions field, and
+// sets the extensions found in node. Fields that node does not set keep their values.
+func unmarshalYAMLObject(node *yaml.Node, v interface{}) error {
+	value := reflect.ValueOf(v).Elem()
+	resolved := node
+	if resolved.Kind == yaml.AliasNode {
+		resolved = resolved.Alias
+	}
+	switch {
+	case resolved.Kind == yaml.ScalarNode && resolved.ShortTag() == "!!null":
+		return nil
+	case resolved.Kind != yaml.MappingNode:
+		return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: cannot unmarshal %s `%s` into %s",
+			resolved.Line, resolved.ShortTag(), resolved.Value, value.Type())}}
+	}
+	plain := toPlain(value, nil)
+	err := node.Decode(plain.Addr().Interface())
+	fromPlain(value, plain)
+	extensions, extErr := yamlExtensions(resolved)
+	if err == nil {
+		err = extErr
+	}
+	value.FieldByName("Extensions").Set(reflect.ValueOf(extensions))
+	return err
+}
+
+// yamlExtensions returns the extensions of a YAML mapping node.
+func yamlExtensions(node *yaml.Node) (Extensions, error) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	var extensions Extensions
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !isExtension(key) {
+			continue
+		}
+		var decoded interface{}
+		if err := node.Content[i+1].Decode(&decoded); err != nil {
+			return nil, err
+		}
+		if extensions == nil {
+			extensions = Extensions{}
+		}
+		extensions[key] = decoded
+	}
+	return extensions, nil
+}
+
+// yamlHasKey reports whether a YAML mapping node has the given key.
+func yamlHasKey(node *yaml.Node, key string) bool {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for i := 0; i+1 < len(node.Content) && node.Kind == yaml.MappingNode; i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalYAMLObject encodes v, a struct with an Extensions field, followed by its extensions.
+// The fields whose names are in explicit are written even when they are empty.
+func marshalYAMLObject(v interface{}, explicit ...string) (interface{}, error) {
+	value := reflect.ValueOf(v)
+	node := &yaml.Node{}
+	if err := node.Encode(toPlain(value, explicit).Interface()); err != nil {
+		return nil, err
+	}
+	if err := appendYAMLExtensions(node, value.FieldByName("Extensions").Interface().(Extensions)); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func appendYAMLExtensions(node *yaml.Node, extensions Extensions) error {
+	for _, key := range sortedKeys(extensions) {
+		if !isExtension(key) {
+			continue
+		}
+		value := &yaml.Node{}
+		if err := value.Encode(extensions[key]); err != nil {
+			return err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+	return nil
+}
+
+// plainStruct is a struct type with the exported fields of a model type but none of its
+// methods, so that encoding/json and yaml.v3 encode it field by field instead of calling
+// back into the model's own marshal methods.
+type plainStruct struct {
+	typ   reflect.Type
+	index []int // The index in the model type of each field of typ.
+}
+
+type plainKey struct {
+	typ      reflect.Type
+	explicit string
+}
+
+var plainStructCache sync.Map // plainKey to *plainStruct.
+
+// plainStructOf returns the plainStruct of struct type t. The omitempty option is dropped
+// from the fields whose JSON names are in explicit.
+func plainStructOf(t reflect.Type, explicit []string) *plainStruct {
+	key := plainKey{t, strings.Join(explicit, ",")}
+	if cached, ok := plainStructCache.Load(key); ok {
+		return cached.(*plainStruct)
+	}
+	plain := &plainStruct{}
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag
+		if name, _, _ := strings.Cut(tag.Get("json"), ","); name != "" && slices.Contains(explicit, name) {
+			tag = reflect.StructTag(strings.ReplaceAll(string(tag), ",omitempty", ""))
+		}
+		fields = append(fields, reflect.StructField{Name: field.Name, Type: field.Type, Tag: tag, Anonymous: field.Anonymous})
+		plain.index = append(plain.index, i)
+	}
+	plain.typ = reflect.StructOf(fields)
+	cached, _ := plainStructCache.LoadOrStore(key, plain)
+	return cached.(*plainStruct)
+}
+
+// toPlain returns an addressable copy of struct value in its plainStruct.
+func toPlain(value reflect.Value, explicit []string) reflect.Value {
+	plain := plainStructOf(value.Type(), explicit)
+	copied := reflect.New(plain.typ).Elem()
+	for i, index := range plain.index {
+		copied.Field(i).Set(value.Field(index))
+	}
+	return copied
+}
+
+// fromPlain copies the fields of plain, made by toPlain, back into the addressable struct value.
+func fromPlain(value, plain reflect.Value) {
+	for i, index := range plainStructOf(value.Type(), nil).index {
+		value.Field(index).Set(plain.Field(i))
+	}
+}
+
+// UnmarshalJSON decodes a Callback, whose expressions are the keys of the object itself.
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Callback{}
+	for key, value := range raw {
+		switch {
+		case key == "$ref":
+			if err := json.Unmarshal(value, &c.Ref); err != nil {
+				return err
+			}
+		case isExtension(key):
+			var decoded interface{}
+			if err := json.Unmarshal(value, &decoded); err != nil {
+				return err
+			}
+			if c.Extensions == nil {
+				c.Extensions = Extensions{}
+			}
+			c.Extensions[key] = decoded
+		default:
+			item := &PathItem{}
+			if err := json.Unmarshal(value, item); err != nil {
+				return err
+			}
+			if c.Expression == nil {
+				c.Expression = map[string]*PathItem{}
+			}
+			c.Expression[key] = item
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes a Callback with its expressions as the keys of the object.
+func (c Callback) MarshalJSON() ([]byte, error) {
+	object := make(map[string]interface{}, len(c.Expression)+len(c.Extensions)+1)
+	for key, item := range c.Expression {
+		object[key] = item
+	}
+	for key, value := range c.Extensions {
+		if isExtension(key) {
+			object[key] = value
+		}
+	}
+	if c.Ref != "" {
+		object["$ref"] = c.Ref
+	}
+	return json.Marshal(object)
+}
+
+// UnmarshalYAML decodes a Callback, whose expressions are the keys of the mapping itself.
+func (c *Callback) UnmarshalYAML(node *yaml.Node) error {
+	var raw map[string]yaml.Node
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*c = Callback{}
+	for key, value := range raw {
+		switch {
+		case key == "$ref":
+			if err := value.Decode(&c.Ref); err != nil {
+				return err
+			}
+		case isExtension(key):
+			var decoded interface{}
+			if err := value.Decode(&decoded); err != nil {
+				return err
+			}
+			if c.Extensions == nil {
+				c.Extensions = Extensions{}
+			}
+			c.Extensions[key] = decoded
+		default:
+			item := &PathItem{}
+			if err := value.Decode(item); err != nil {
+				return err
+			}
+			if c.Expression == nil {
+				c.Expression = map[string]*PathItem{}
+			}
+			c.Expression[key] = item
+		}
+	}
+	return nil
+}
+
+// MarshalYAML encodes a Callback with its expressions as the keys of the mapping.
+func (c Callback) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if c.Ref != "" {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "$ref"},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: c.Ref})
+	}
+	for _, key := range sortedKeys(c.Expression) {
+		value := &yaml.Node{}
+		if err := value.Encode(c.Expression[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+	if err := appendYAMLExtensions(node, c.Extensions); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an OpenAPI with its extensions.
+func (o *OpenAPI) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, o) }
+func (o OpenAPI) MarshalJSON() ([]byte, error)         { return marshalJSONObject(o) }
+func (o *OpenAPI) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, o) }
+func (o OpenAPI) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(o) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an Info with its extensions.
+func (i *Info) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, i) }
+func (i Info) MarshalJSON() ([]byte, error)         { return marshalJSONObject(i) }
+func (i *Info) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, i) }
+func (i Info) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(i) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Contact with its extensions.
+func (c *Contact) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, c) }
+func (c Contact) MarshalJSON() ([]byte, error)         { return marshalJSONObject(c) }
+func (c *Contact) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, c) }
+func (c Contact) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(c) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a License with its extensions.
+func (l *License) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, l) }
+func (l License) MarshalJSON() ([]byte, error)         { return marshalJSONObject(l) }
+func (l *License) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, l) }
+func (l License) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(l) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Server with its extensions.
+func (s *Server) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, s) }
+func (s Server) MarshalJSON() ([]byte, error)         { return marshalJSONObject(s) }
+func (s *Server) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, s) }
+func (s Server) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(s) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a ServerVariable with its extensions.
+func (s *ServerVariable) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, s) }
+func (s ServerVariable) MarshalJSON() ([]byte, error)         { return marshalJSONObject(s) }
+func (s *ServerVariable) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, s) }
+func (s ServerVariable) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(s) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Tag with its extensions.
+func (t *Tag) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, t) }
+func (t Tag) MarshalJSON() ([]byte, error)         { return marshalJSONObject(t) }
+func (t *Tag) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, t) }
+func (t Tag) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(t) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an ExternalDocumentation with its extensions.
+func (e *ExternalDocumentation) UnmarshalJSON(data []byte) error { return unmarshalJSONObject(data, e) }
+func (e ExternalDocumentation) MarshalJSON() ([]byte, error)     { return marshalJSONObject(e) }
+func (e *ExternalDocumentation) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAMLObject(node, e)
+}
+func (e ExternalDocumentation) MarshalYAML() (interface{}, error) { return marshalYAMLObject(e) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Path with its extensions.
+func (p *Path) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, p) }
+func (p Path) MarshalJSON() ([]byte, error)         { return marshalJSONObject(p) }
+func (p *Path) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, p) }
+func (p Path) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(p) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an Operation with its extensions.
+func (o *Operation) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, o) }
+func (o Operation) MarshalJSON() ([]byte, error)         { return marshalJSONObject(o) }
+func (o *Operation) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, o) }
+func (o Operation) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(o) }
+
+// UnmarshalJSON decodes a Parameter and its extensions.
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalJSONObjectFields(data, p)
+	_, p.explodeSet = raw["explode"]
+	return err
+}
+
+// MarshalJSON encodes a Parameter and its extensions, and explode when it is set to false.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	return marshalJSONObject(p, p.explicit()...)
+}
+
+// UnmarshalYAML decodes a Parameter and its extensions.
+func (p *Parameter) UnmarshalYAML(node *yaml.Node) error {
+	p.explodeSet = yamlHasKey(node, "explode")
+	return unmarshalYAMLObject(node, p)
+}
+
+// MarshalYAML encodes a Parameter and its extensions, and explode when it is set to false.
+func (p Parameter) MarshalYAML() (interface{}, error) {
+	return marshalYAMLObject(p, p.explicit()...)
+}
+
+// explicit returns the empty fields of p that must be encoded: explode when the document sets it to false.
+func (p Parameter) explicit() []string {
+	if p.explodeSet && !p.Explode {
+		return []string{"explode"}
+	}
+	return nil
+}
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a RequestBody with its extensions.
+func (r *RequestBody) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, r) }
+func (r RequestBody) MarshalJSON() ([]byte, error)         { return marshalJSONObject(r) }
+func (r *RequestBody) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, r) }
+func (r RequestBody) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(r) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Response with its extensions.
+func (r *Response) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, r) }
+func (r Response) MarshalJSON() ([]byte, error)         { return marshalJSONObject(r) }
+func (r *Response) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, r) }
+func (r Response) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(r) }
+
+// UnmarshalJSON decodes a Header and its extensions.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalJSONObjectFields(data, h)
+	_, h.explodeSet = raw["explode"]
+	return err
+}
+
+// MarshalJSON encodes a Header and its extensions, and explode when it is set to false.
+func (h Header) MarshalJSON() ([]byte, error) {
+	return marshalJSONObject(h, h.explicit()...)
+}
+
+// UnmarshalYAML decodes a Header and its extensions.
+func (h *Header) UnmarshalYAML(node *yaml.Node) error {
+	h.explodeSet = yamlHasKey(node, "explode")
+	return unmarshalYAMLObject(node, h)
+}
+
+// MarshalYAML encodes a Header and its extensions, and explode when it is set to false.
+func (h Header) MarshalYAML() (interface{}, error) {
+	return marshalYAMLObject(h, h.explicit()...)
+}
+
+// explicit returns the empty fields of h that must be encoded: explode when the document sets it to false.
+func (h Header) explicit() []string {
+	if h.explodeSet && !h.Explode {
+		return []string{"explode"}
+	}
+	return nil
+}
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a MediaType with its extensions.
+func (m *MediaType) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, m) }
+func (m MediaType) MarshalJSON() ([]byte, error)         { return marshalJSONObject(m) }
+func (m *MediaType) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, m) }
+func (m MediaType) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(m) }
+
+// UnmarshalJSON decodes a Encoding and its extensions.
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	raw, err := unmarshalJSONObjectFields(data, e)
+	_, e.explodeSet = raw["explode"]
+	return err
+}
+
+// MarshalJSON encodes a Encoding and its extensions, and explode when it is set to false.
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	return marshalJSONObject(e, e.explicit()...)
+}
+
+// UnmarshalYAML decodes a Encoding and its extensions.
+func (e *Encoding) UnmarshalYAML(node *yaml.Node) error {
+	e.explodeSet = yamlHasKey(node, "explode")
+	return unmarshalYAMLObject(node, e)
+}
+
+// MarshalYAML encodes a Encoding and its extensions, and explode when it is set to false.
+func (e Encoding) MarshalYAML() (interface{}, error) {
+	return marshalYAMLObject(e, e.explicit()...)
+}
+
+// explicit returns the empty fields of e that must be encoded: explode when the document sets it to false.
+func (e Encoding) explicit() []string {
+	if e.explodeSet && !e.Explode {
+		return []string{"explode"}
+	}
+	return nil
+}
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an Example with its extensions.
+func (e *Example) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, e) }
+func (e Example) MarshalJSON() ([]byte, error)         { return marshalJSONObject(e) }
+func (e *Example) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, e) }
+func (e Example) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(e) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Link with its extensions.
+func (l *Link) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, l) }
+func (l Link) MarshalJSON() ([]byte, error)         { return marshalJSONObject(l) }
+func (l *Link) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, l) }
+func (l Link) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(l) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Components with its extensions.
+func (c *Components) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, c) }
+func (c Components) MarshalJSON() ([]byte, error)         { return marshalJSONObject(c) }
+func (c *Components) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, c) }
+func (c Components) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(c) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a SecurityScheme with its extensions.
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, s) }
+func (s SecurityScheme) MarshalJSON() ([]byte, error)         { return marshalJSONObject(s) }
+func (s *SecurityScheme) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, s) }
+func (s SecurityScheme) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(s) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an OAuthFlows with its extensions.
+func (o *OAuthFlows) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, o) }
+func (o OAuthFlows) MarshalJSON() ([]byte, error)         { return marshalJSONObject(o) }
+func (o *OAuthFlows) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, o) }
+func (o OAuthFlows) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(o) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode an OAuthFlow with its extensions.
+func (o *OAuthFlow) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, o) }
+func (o OAuthFlow) MarshalJSON() ([]byte, error)         { return marshalJSONObject(o) }
+func (o *OAuthFlow) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, o) }
+func (o OAuthFlow) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(o) }
+
+// UnmarshalJSON decodes a Schema and its extensions, or a boolean schema.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	switch trimmed := bytes.TrimSpace(data); string(trimmed) {
+	case "true", "false":
+		b := trimmed[0] == 't'
+		*s = Schema{Bool: &b}
+		return nil
+	}
+	raw, err := unmarshalJSONObjectFields(data, s)
+	s.constNull = s.Const == nil && string(bytes.TrimSpace(raw["const"])) == "null"
+	return err
+}
+
+// MarshalJSON encodes a Schema and its extensions, or a boolean schema.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Bool != nil {
+		return json.Marshal(*s.Bool)
+	}
+	return marshalJSONObject(s, s.explicit()...)
+}
+
+// UnmarshalYAML decodes a Schema and its extensions, or a boolean schema.
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!bool" {
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return err
+		}
+		*s = Schema{Bool: &b}
+		return nil
+	}
+	err := unmarshalYAMLObject(node, s)
+	s.constNull = s.Const == nil && yamlHasKey(node, "const")
+	return err
+}
+
+// MarshalYAML encodes a Schema and its extensions, or a boolean schema.
+func (s Schema) MarshalYAML() (interface{}, error) {
+	if s.Bool != nil {
+		return *s.Bool, nil
+	}
+	return marshalYAMLObject(s, s.explicit()...)
+}
+
+// explicit returns the empty fields of s that must be encoded: const when the document sets it to null.
+func (s Schema) explicit() []string {
+	if s.constNull {
+		return []string{"const"}
+	}
+	return nil
+}
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a XML with its extensions.
+func (x *XML) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, x) }
+func (x XML) MarshalJSON() ([]byte, error)         { return marshalJSONObject(x) }
+func (x *XML) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, x) }
+func (x XML) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(x) }
+
+// UnmarshalJSON decodes a Discriminator and its extensions, or the property name of a Swagger 2.0 discriminator.
+func (d *Discriminator) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		*d = Discriminator{propertyOnly: true}
+		return json.Unmarshal(trimmed, &d.PropertyName)
+	}
+	return unmarshalJSONObject(data, d)
+}
+
+// MarshalJSON encodes a Discriminator and its extensions, or its property name alone for Swagger 2.0.
+func (d Discriminator) MarshalJSON() ([]byte, error) {
+	if d.propertyOnly {
+		return json.Marshal(d.PropertyName)
+	}
+	return marshalJSONObject(d)
+}
+
+// UnmarshalYAML decodes a Discriminator and its extensions, or the property name of a Swagger 2.0 discriminator.
+func (d *Discriminator) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" {
+		*d = Discriminator{PropertyName: node.Value, propertyOnly: true}
+		return nil
+	}
+	return unmarshalYAMLObject(node, d)
+}
+
+// MarshalYAML encodes a Discriminator and its extensions, or its property name alone for Swagger 2.0.
+func (d Discriminator) MarshalYAML() (interface{}, error) {
+	if d.propertyOnly {
+		return d.PropertyName, nil
+	}
+	return marshalYAMLObject(d)
+}
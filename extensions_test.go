@@ -0,0 +1,89 @@
+package oas
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtensionsRoundTrip(t *testing.T) {
+	source, err := os.ReadFile("testdata/extensions.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want interface{}
+	if err := yaml.Unmarshal(source, &want); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("YAML", func(t *testing.T) {
+		doc := &OpenAPI{}
+		if err := yaml.Unmarshal(source, doc); err != nil {
+			t.Fatal(err)
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got interface{}
+		if err := yaml.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip changed the document:\n%s", data)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		source, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc := &OpenAPI{}
+		if err := json.Unmarshal(source, doc); err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, wantJSON interface{}
+		json.Unmarshal(data, &got)
+		json.Unmarshal(source, &wantJSON)
+		if !reflect.DeepEqual(got, wantJSON) {
+			t.Errorf("round trip changed the document:\n%s", data)
+		}
+	})
+}
+
+func TestExtensionsAccessors(t *testing.T) {
+	doc, err := LoadFile("testdata/extensions.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := doc.Components.Schemas["Pet"].Extensions
+	var nested struct {
+		Nested []interface{} `json:"nested"`
+	}
+	if err := e.Decode("x-schema", &nested); err != nil || len(nested.Nested) != 2 {
+		t.Errorf("Decode(x-schema) = %v, %v", nested, err)
+	}
+	if _, err := doc.Extensions.Get("x-missing"); err {
+		t.Error("Get(x-missing) found a value")
+	}
+	if n, ok := doc.Extensions.Int("x-root"); !ok || n != 1 {
+		t.Errorf("Int(x-root) = %d, %t, want 1", n, ok)
+	}
+	if b, ok := doc.Info.Extensions.Bool("x-info"); !ok || !b {
+		t.Errorf("Bool(x-info) = %t, %t, want true", b, ok)
+	}
+	if s, ok := doc.Paths["/pets"].Post.Extensions.String("x-operation"); !ok || s != "o" {
+		t.Errorf("String(x-operation) = %q, %t, want o", s, ok)
+	}
+	if got := *doc.Components.Schemas["Pet"].Properties["size"].MaxLength; got != 1<<31 {
+		t.Errorf("maxLength = %d, want %d", got, 1<<31)
+	}
+}
@@ -0,0 +1,338 @@
+package oas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// Limits of multipart bodies when WithMaxFileSize and WithMaxParts are not given.
+const (
+	defaultMaxFileSize = 32 << 20
+	defaultMaxParts    = 1000
+)
+
+// WithMaxFileSize limits the size of each part of a multipart body, in bytes.
+func WithMaxFileSize(size int64) BodyOption {
+	return func(o *bodyOptions) {
+		o.maxFileSize = size
+	}
+}
+
+// WithMaxParts limits the number of parts of a multipart body. The default is 1000.
+func WithMaxParts(n int) BodyOption {
+	return func(o *bodyOptions) {
+		o.maxParts = n
+	}
+}
+
+// resolveSchema follows the reference of s, returning s itself when it cannot be resolved.
+func (o *OpenAPI) resolveSchema(s *Schema) *Schema {
+	if s == nil || s.Ref == "" {
+		return s
+	}
+	if target, err := o.resolveRef(s.Ref); err == nil {
+		if resolved, ok := target.(*Schema); ok {
+			return resolved
+		}
+	}
+	return s
+}
+
+// propertySchema returns the resolved schema of a property of the object schema s, or nil.
+func (o *OpenAPI) propertySchema(s *Schema, name string) *Schema {
+	if s = o.resolveSchema(s); s == nil {
+		return nil
+	}
+	return o.resolveSchema(s.Properties[name])
+}
+
+// decodeURLEncoded decodes an application/x-www-form-urlencoded body into the object its
+// schema describes. Values are coerced to the types of their property schemas and arrays and
+// objects are split according to the style and explode of their Encoding entries.
+func (o *OpenAPI) decodeURLEncoded(mediaType *MediaType, body []byte) (map[string]interface{}, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding form: %w", err)
+	}
+	object := map[string]interface{}{}
+	deep := map[string]map[string]interface{}{}
+	for _, key := range sortedKeys(values) {
+		if name, property, ok := deepObjectKey(key); ok {
+			if enc := mediaType.Encoding[name]; enc != nil && enc.Style == "deepObject" {
+				if deep[name] == nil {
+					deep[name] = map[string]interface{}{}
+				}
+				deep[name][property] = coerce(typesOf(o.propertySchema(o.propertySchema(mediaType.Schema, name), property)), values.Get(key))
+				continue
+			}
+		}
+		object[key] = o.formValue(mediaType.Schema, mediaType.Encoding[key], key, values[key])
+	}
+	for name, value := range deep {
+		object[name] = value
+	}
+	return object, nil
+}
+
+// deepObjectKey splits a key of the form "name[property]".
+func deepObjectKey(key string) (name, property string, ok bool) {
+	name, rest, ok := strings.Cut(key, "[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return "", "", false
+	}
+	return name, strings.TrimSuffix(rest, "]"), true
+}
+
+// formValue converts the values of a form field to the value its property schema expects.
+func (o *OpenAPI) formValue(schema *Schema, enc *Encoding, name string, values []string) interface{} {
+	property := o.propertySchema(schema, name)
+	style, explode := "form", true
+	if enc != nil {
+		if enc.Style != "" {
+			style = enc.Style
+		}
+		explode = enc.IsExploded()
+	}
+
+	switch {
+	case property != nil && property.Type.Includes("array"):
+		if len(values) == 1 && !explode {
+			values = strings.Split(values[0], delimiterOf(style))
+		}
+		items := make([]interface{}, len(values))
+		itemTypes := typesOf(o.resolveSchema(property.Items))
+		for i, value := range values {
+			items[i] = coerce(itemTypes, value)
+		}
+		return items
+	case property != nil && property.Type.Includes("object") && len(values) == 1:
+		// A non-exploded form object is a list of alternating names and values.
+		if style == "form" && !explode {
+			parts := strings.Split(values[0], ",")
+			object := map[string]interface{}{}
+			for i := 0; i+1 < len(parts); i += 2 {
+				object[parts[i]] = coerce(typesOf(o.propertySchema(property, parts[i])), parts[i+1])
+			}
+			return object
+		}
+		var object interface{}
+		if err := json.Unmarshal([]byte(values[0]), &object); err == nil {
+			return object
+		}
+	}
+	if len(values) > 1 {
+		items := make([]interface{}, len(values))
+		for i, value := range values {
+			items[i] = value
+		}
+		return items
+	}
+	return coerce(typesOf(property), values[0])
+}
+
+// delimiterOf returns the delimiter of non-exploded array values in a style.
+func delimiterOf(style string) string {
+	switch style {
+	case "spaceDelimited":
+		return " "
+	case "pipeDelimited":
+		return "|"
+	}
+	return ","
+}
+
+// typesOf returns the types of a schema, or none when it is nil.
+func typesOf(s *Schema) Types {
+	if s == nil {
+		return nil
+	}
+	return s.Type
+}
+
+// decodeMultipart decodes a multipart/form-data body into the object its schema describes,
+// reading one part at a time from r. Each part is checked against its Encoding entry: its
+// Content-Type must be one of those listed, or the default of its property schema, and the
+// headers the entry declares must be present and valid. A part may not exceed the maximum
+// file size, nor the maxLength of a binary property. Parts whose property schema is a string
+// hold their content as a string, JSON parts are decoded, and other parts are coerced to the
+// type of their property schema. Parts repeated for an array property are collected into an
+// array.
+func (o *OpenAPI) decodeMultipart(mediaType *MediaType, contentType string, r io.Reader, options *bodyOptions) (map[string]interface{}, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["boundary"] == "" {
+		return nil, errors.New("multipart body has no boundary")
+	}
+	maxSize := options.maxFileSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxFileSize
+	}
+	maxParts := options.maxParts
+	if maxParts <= 0 {
+		maxParts = defaultMaxParts
+	}
+
+	reader := multipart.NewReader(r, params["boundary"])
+	object := map[string]interface{}{}
+	var errs ValidationErrors
+	for parts := 1; ; parts++ {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding multipart body: %w", err)
+		}
+		if parts > maxParts {
+			return nil, fmt.Errorf("multipart body has more than %d parts", maxParts)
+		}
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		pointer := "/" + pointerEscape(name)
+		property := o.propertySchema(mediaType.Schema, name)
+		schema := property
+		if property != nil && property.Type.Includes("array") {
+			schema = o.resolveSchema(property.Items)
+		}
+
+		limit, limitErr := maxSize, fmt.Errorf("part exceeds the maximum size of %d bytes", maxSize)
+		if isBinary(schema) && schema.MaxLength != nil && int64(*schema.MaxLength) < limit {
+			limit, limitErr = int64(*schema.MaxLength), fmt.Errorf("part exceeds the maximum length of %d bytes", *schema.MaxLength)
+		}
+		data, err := io.ReadAll(io.LimitReader(part, limit+1))
+		if err != nil {
+			return nil, fmt.Errorf("error reading part '%s': %w", name, err)
+		}
+		if int64(len(data)) > limit {
+			errs = append(errs, ValidationError{Err: limitErr, Field: pointer})
+			continue
+		}
+
+		enc := mediaType.Encoding[name]
+		defaultType := defaultPartType(schema)
+		partType := part.Header.Get("Content-Type")
+		if partType == "" {
+			partType = defaultType
+		}
+		allowed := defaultType
+		if enc != nil && enc.ContentType != "" {
+			allowed = enc.ContentType
+		} else if defaultType == "application/octet-stream" {
+			allowed = "" // Binary content of any type is allowed by default.
+		}
+		errs = append(errs, checkPartType(allowed, partType, pointer)...)
+		if enc != nil {
+			errs = append(errs, o.checkPartHeaders(enc, part.Header, pointer)...)
+		}
+
+		if property != nil && property.Type.Includes("array") {
+			items, _ := object[name].([]interface{})
+			object[name] = append(items, o.partValue(schema, partType, data))
+			continue
+		}
+		object[name] = o.partValue(property, partType, data)
+	}
+	if len(errs) > 0 {
+		return object, errs
+	}
+	return object, nil
+}
+
+// isBinary reports whether s describes binary content, which maxLength limits in bytes.
+func isBinary(s *Schema) bool {
+	return s != nil && s.Type.Includes("string") && s.Format == "binary"
+}
+
+// defaultPartType returns the content type of a part whose Encoding entry lists none, as
+// the specification defines it for the schema of its property.
+func defaultPartType(s *Schema) string {
+	switch {
+	case isBinary(s):
+		return "application/octet-stream"
+	case s != nil && s.Type.Includes("object"):
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+// partValue converts the content of a part to the value its property schema expects.
+func (o *OpenAPI) partValue(property *Schema, partType string, data []byte) interface{} {
+	structured := property != nil && (property.Type.Includes("object") || property.Type.Includes("array"))
+	if t, ok := parseMediaRange(partType); ok && (t.isJSON() || structured) {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value
+		}
+	}
+	if property == nil || property.Type.Includes("string") {
+		return string(data)
+	}
+	return coerce(property.Type, string(data))
+}
+
+// checkPartType checks the Content-Type of a part against the comma-separated list of
+// allowed types. An empty list allows any type.
+func checkPartType(allowed, partType, pointer string) ValidationErrors {
+	if allowed == "" {
+		return nil
+	}
+	types := map[string]*MediaType{}
+	for _, t := range strings.Split(allowed, ",") {
+		types[strings.TrimSpace(t)] = nil
+	}
+	if _, _, ok := MatchContentType(types, partType); !ok {
+		return ValidationErrors{{Err: fmt.Errorf("part content type '%s' is not one of %s", partType, allowed), Field: pointer}}
+	}
+	return nil
+}
+
+// checkPartHeaders checks the headers of a part against its Encoding entry.
+func (o *OpenAPI) checkPartHeaders(enc *Encoding, header textproto.MIMEHeader, pointer string) ValidationErrors {
+	var errs ValidationErrors
+	for _, name := range sortedKeys(enc.Headers) {
+		h := enc.Headers[name]
+		if strings.EqualFold(name, "Content-Type") || h == nil {
+			continue
+		}
+		if h.Ref != "" {
+			target, err := o.resolveRef(h.Ref)
+			if resolved, ok := target.(*Header); err == nil && ok {
+				h = resolved
+			}
+		}
+		values, present := header[textproto.CanonicalMIMEHeaderKey(name)]
+		if !present {
+			if h.Required {
+				errs = append(errs, ValidationError{Err: fmt.Errorf("part header '%s' is required", name), Field: pointer})
+			}
+			continue
+		}
+		if h.Schema == nil {
+			continue
+		}
+		v, err := o.validator(h.Schema, AnyDirection)
+		if err != nil {
+			errs = append(errs, ValidationError{Err: err, Field: pointer})
+			continue
+		}
+		if err := v.Validate(coerce(typesOf(o.resolveSchema(h.Schema)), values[0]), false, false); err != nil {
+			var failures ValidationErrors
+			if errors.As(err, &failures) {
+				for _, failure := range failures {
+					failure.Err = fmt.Errorf("part header '%s': %w", name, failure.Err)
+					failure.Field = pointer + failure.Field
+					errs = append(errs, failure)
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,136 @@
+package oas
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+const uploadDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Uploads", "version": "1"},
+	"paths": {"/uploads": {"post": {
+		"requestBody": {"required": true, "content": {"multipart/form-data": {
+			"schema": {
+				"type": "object",
+				"required": ["file"],
+				"properties": {
+					"file": {"type": "string", "format": "binary", "maxLength": 8},
+					"icon": {"type": "string", "format": "binary"},
+					"meta": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}},
+					"count": {"type": "integer"},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				}
+			},
+			"encoding": {"icon": {"contentType": "image/png, image/gif"}}
+		}}},
+		"responses": {"204": {"description": "stored"}}
+	}}}
+}`
+
+// multipartPart is a part of a multipart body built by multipartBody.
+type multipartPart struct {
+	name, contentType, content string
+}
+
+// multipartBody returns a multipart/form-data body of parts and its Content-Type.
+func multipartBody(t *testing.T, parts ...multipartPart) ([]byte, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		header := textproto.MIMEHeader{"Content-Disposition": {`form-data; name="` + p.name + `"; filename="` + p.name + `"`}}
+		if p.contentType != "" {
+			header.Set("Content-Type", p.contentType)
+		}
+		part, err := w.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(p.content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), w.FormDataContentType()
+}
+
+func TestValidateMultipartRequestBody(t *testing.T) {
+	doc, err := LoadJSON([]byte(uploadDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/uploads"].Post
+	tests := []struct {
+		name  string
+		parts []multipartPart
+		opts  []BodyOption
+		err   string // A substring of the expected error, or empty when the body is valid.
+	}{
+		{name: "file of any type", parts: []multipartPart{{"file", "image/jpeg", "12345678"}}},
+		{name: "file without a content type", parts: []multipartPart{{"file", "", "1234"}}},
+		{name: "file longer than maxLength", parts: []multipartPart{{"file", "", "123456789"}}, err: "maximum length of 8 bytes"},
+		{name: "file larger than the maximum size", parts: []multipartPart{{"file", "", "12345"}}, opts: []BodyOption{WithMaxFileSize(4)}, err: "maximum size of 4 bytes"},
+		{name: "missing file", parts: []multipartPart{{"count", "", "1"}}, err: "file"},
+		{name: "icon of a listed type", parts: []multipartPart{{"file", "", "1"}, {"icon", "image/gif", "GIF89a"}}},
+		{name: "icon of another type", parts: []multipartPart{{"file", "", "1"}, {"icon", "image/jpeg", "jpeg"}}, err: "not one of image/png, image/gif"},
+		{name: "JSON object", parts: []multipartPart{{"file", "", "1"}, {"meta", "application/json", `{"name": "a"}`}}},
+		{name: "object without a content type", parts: []multipartPart{{"file", "", "1"}, {"meta", "", `{"name": "a"}`}}},
+		{name: "object sent as text", parts: []multipartPart{{"file", "", "1"}, {"meta", "text/plain", `{"name": "a"}`}}, err: "not one of application/json"},
+		{name: "invalid object", parts: []multipartPart{{"file", "", "1"}, {"meta", "", `{}`}}, err: "name"},
+		{name: "integer", parts: []multipartPart{{"file", "", "1"}, {"count", "", "3"}}},
+		{name: "invalid integer", parts: []multipartPart{{"file", "", "1"}, {"count", "", "three"}}, err: "count"},
+		{name: "integer sent as JSON", parts: []multipartPart{{"file", "", "1"}, {"count", "application/json", "3"}}, err: "not one of text/plain"},
+		{name: "too many parts", parts: []multipartPart{{"file", "", "1"}, {"tags", "", "a"}, {"tags", "", "b"}}, opts: []BodyOption{WithMaxParts(2)}, err: "more than 2 parts"},
+		{name: "body larger than the maximum size", parts: []multipartPart{{"file", "", "1"}, {"tags", "", "a"}}, opts: []BodyOption{WithMaxBodySize(64)}, err: "maximum size of 64 bytes"},
+		{name: "repeated array parts", parts: []multipartPart{{"file", "", "1"}, {"tags", "", "a"}, {"tags", "text/plain; charset=utf-8", "b"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, contentType := multipartBody(t, test.parts...)
+			err := doc.ValidateRequestBody(op, contentType, body, test.opts...)
+			if test.err == "" && err != nil {
+				t.Errorf("ValidateRequestBody() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("ValidateRequestBody() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestBodyReaderStreamsParts(t *testing.T) {
+	doc, err := LoadJSON([]byte(uploadDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := doc.Paths["/uploads"].Post
+
+	// The file part is far larger than the limit and is written while the body is validated.
+	r, w := io.Pipe()
+	mw := multipart.NewWriter(w)
+	go func() {
+		part, err := mw.CreateFormFile("icon", "icon.png")
+		if err == nil {
+			chunk := bytes.Repeat([]byte{0}, 1<<16)
+			for i := 0; i < 64 && err == nil; i++ {
+				_, err = part.Write(chunk)
+			}
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		w.CloseWithError(err)
+	}()
+	err = doc.ValidateRequestBodyReader(op, mw.FormDataContentType(), r, WithMaxFileSize(1<<10))
+	if err == nil || !strings.Contains(err.Error(), "maximum size of 1024 bytes") {
+		t.Errorf("ValidateRequestBodyReader() = %v, want a size error", err)
+	}
+
+	if err := doc.ValidateRequestBodyReader(op, "multipart/form-data; boundary=x", strings.NewReader("")); err == nil {
+		t.Error("ValidateRequestBodyReader() with an empty body = nil, want an error for the required body")
+	}
+}
@@ -0,0 +1,217 @@
+package oas
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// goValue converts a Go value to the form it takes in JSON, so that it can be validated
+// like a decoded document: pointers are followed, structs become maps keyed by their json
+// tags, byte slices become base64 strings, json.Number becomes int64 or float64 and
+// json.Marshaler implementations are marshaled. A time.Time becomes a string formatted
+// for format, "date", "time" or otherwise "date-time". Nested values are converted when
+// they are validated.
+func goValue(i interface{}, format string) interface{} {
+	for i != nil {
+		switch x := i.(type) {
+		case time.Time:
+			return formatTime(x, format)
+		case *time.Time:
+			if x == nil {
+				return nil
+			}
+			return formatTime(*x, format)
+		case json.Number:
+			if n, err := x.Int64(); err == nil {
+				return n
+			}
+			f, _ := x.Float64()
+			return f
+		case json.Marshaler:
+			if value := reflect.ValueOf(i); value.Kind() == reflect.Pointer && value.IsNil() {
+				return nil
+			}
+			data, err := x.MarshalJSON()
+			if err != nil {
+				return i
+			}
+			var decoded interface{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				return i
+			}
+			return decoded
+		}
+
+		value := reflect.ValueOf(i)
+		switch value.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if value.IsNil() {
+				return nil
+			}
+			i = value.Elem().Interface()
+		case reflect.Struct:
+			return structMap(value)
+		case reflect.Slice:
+			if value.IsNil() {
+				return nil
+			}
+			if value.Type().Elem().Kind() == reflect.Uint8 {
+				return base64.StdEncoding.EncodeToString(value.Bytes())
+			}
+			return i
+		case reflect.Map:
+			if value.IsNil() {
+				return nil
+			}
+			return i
+		default:
+			return i
+		}
+	}
+	return nil
+}
+
+// formatTime formats t as the string format describes.
+func formatTime(t time.Time, format string) string {
+	switch format {
+	case "date":
+		return t.Format("2006-01-02")
+	case "time":
+		return t.Format("15:04:05.999999999Z07:00")
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// structMap returns the fields of a struct keyed by the names encoding/json would use.
+func structMap(value reflect.Value) map[string]interface{} {
+	fields := jsonFields(value.Type())
+	m := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		f, err := value.FieldByIndexErr(field.index)
+		if err != nil || (field.omitEmpty && isEmptyValue(f)) {
+			continue
+		}
+		if field.quoted {
+			m[field.name] = quotedValue(f)
+			continue
+		}
+		m[field.name] = f.Interface()
+	}
+	return m
+}
+
+// quotedValue returns the value of a field tagged ",string" as encoding/json encodes it.
+func quotedValue(f reflect.Value) interface{} {
+	switch f.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(f.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(f.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'g', -1, f.Type().Bits())
+	}
+	return f.Interface()
+}
+
+// jsonField is a struct field as encoding/json sees it.
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	quoted    bool
+}
+
+var jsonFieldCache sync.Map // map[reflect.Type][]jsonField
+
+// jsonFields returns the fields encoding/json encodes for struct type t. Fields of embedded
+// structs are promoted unless a shallower field has the same name.
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+	var fields []jsonField
+	seen := map[string]bool{}
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, f)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		seen[name] = true
+		fields = append(fields, jsonField{
+			name:      name,
+			index:     f.Index,
+			omitEmpty: strings.Contains(","+flags+",", ",omitempty,"),
+			quoted:    strings.Contains(","+flags+",", ",string,"),
+		})
+	}
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		for _, promoted := range jsonFields(ft) {
+			if seen[promoted.name] {
+				continue
+			}
+			seen[promoted.name] = true
+			promoted.index = append(append([]int{}, f.Index...), promoted.index...)
+			fields = append(fields, promoted)
+		}
+	}
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+// isEmptyValue reports whether a field tagged ",omitempty" is omitted by encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// mapIndex returns the element of a map with a string-kinded key, or the zero Value when
+// the key type cannot hold name or the map has no such element.
+func mapIndex(m reflect.Value, name string) reflect.Value {
+	key := reflect.ValueOf(name)
+	switch keyType := m.Type().Key(); {
+	case keyType.Kind() == reflect.String:
+		key = key.Convert(keyType)
+	case !key.Type().AssignableTo(keyType):
+		return reflect.Value{}
+	}
+	return m.MapIndex(key)
+}
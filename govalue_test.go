@@ -0,0 +1,90 @@
+package oas
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type goValueBase struct {
+	ID uint8 `json:"id"`
+}
+
+type goValuePet struct {
+	goValueBase
+	Name     string             `json:"name"`
+	Nickname *string            `json:"nickname,omitempty"`
+	Age      int64              `json:"age,string"`
+	Born     time.Time          `json:"born"`
+	Photo    []byte             `json:"photo,omitempty"`
+	Color    goValueColor       `json:"color,omitempty"`
+	Labels   map[goValueKey]int `json:"labels,omitempty"`
+	Secret   string             `json:"-"`
+	internal string
+}
+
+type goValueKey string
+
+// goValueColor encodes as an RGB array.
+type goValueColor struct {
+	R, G, B uint8
+}
+
+func (c goValueColor) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{int(c.R), int(c.G), int(c.B)})
+}
+
+func TestValidateGoValue(t *testing.T) {
+	var s Schema
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["id", "name", "age", "born"],
+		"additionalProperties": false,
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "minLength": 1},
+			"nickname": {"type": "string", "maxLength": 4},
+			"age": {"type": "string", "pattern": "^[0-9]+$"},
+			"born": {"type": "string", "format": "date"},
+			"photo": {"type": "string", "maxLength": 4},
+			"color": {"type": "array", "items": {"type": "integer", "maximum": 200}},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer", "minimum": 0}}
+		}
+	}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	born := time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC)
+	long := "Rexie"
+	tests := []struct {
+		name  string
+		value interface{}
+		err   string // A substring of the expected error, or empty when the value is valid.
+	}{
+		{name: "struct", value: goValuePet{goValueBase: goValueBase{ID: 1}, Name: "Rex", Age: 3, Born: born, Secret: "x", internal: "y"}},
+		{name: "pointer to a struct", value: &goValuePet{goValueBase: goValueBase{ID: 1}, Name: "Rex", Born: born}},
+		{name: "promoted field", value: goValuePet{Name: "Rex", Born: born}, err: "/id"},
+		{name: "pointer field", value: goValuePet{goValueBase: goValueBase{ID: 1}, Name: "Rex", Nickname: &long, Born: born}, err: "/nickname"},
+		{name: "bytes as base64", value: goValuePet{goValueBase: goValueBase{ID: 1}, Name: "Rex", Born: born, Photo: []byte{1, 2, 3, 4}}, err: "/photo"},
+		{name: "json.Marshaler", value: goValuePet{goValueBase: goValueBase{ID: 1}, Name: "Rex", Born: born, Color: goValueColor{R: 255}}, err: "/color/0"},
+		{name: "map with a named key type", value: goValuePet{goValueBase: goValueBase{ID: 1}, Name: "Rex", Born: born, Labels: map[goValueKey]int{"a": -1}}, err: "/labels/a"},
+		{name: "nil pointer", value: (*goValuePet)(nil), err: "input is nil"},
+		{name: "map", value: map[string]interface{}{"id": json.Number("1"), "name": "Rex", "age": "3", "born": "2020-05-17"}},
+		{name: "time as a date", value: map[string]interface{}{"id": 1, "name": "Rex", "age": "3", "born": born}},
+		{name: "unsigned integer", value: map[string]interface{}{"id": uint64(1), "name": "Rex", "age": "3", "born": born}},
+		{name: "unsigned integer out of range", value: map[string]interface{}{"id": uint64(math.MaxUint64), "name": "Rex", "age": "3", "born": born}, err: "out of range"},
+		{name: "fractional json.Number", value: map[string]interface{}{"id": json.Number("1.5"), "name": "Rex", "age": "3", "born": born}, err: "expected integer"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := s.Validate(test.value, false, false)
+			if test.err == "" && err != nil {
+				t.Errorf("Validate() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
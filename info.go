@@ -1,20 +1,29 @@
 package oas
 
+// Contact represents the contact information for the exposed API
 type Contact struct {
-	Email string `json:"email"`
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"` // The identifying name of the contact person/organization.
+	Url        string     `json:"url,omitempty" yaml:"url,omitempty"`   // The URL pointing to the contact information.
+	Email      string     `json:"email" yaml:"email"`                   // The email address of the contact person/organization.
+	Extensions Extensions `json:"-" yaml:"-"`                           // Specification extensions (x- properties) of the object.
 }
 
+// License represents the license information for the exposed API
 type License struct {
-	Name string `json:"name"`
-	Url  string `json:"url"`
+	Name       string     `json:"name" yaml:"name"`                                 // REQUIRED. The license name used for the API.
+	Identifier string     `json:"identifier,omitempty" yaml:"identifier,omitempty"` // An SPDX license expression for the API (OpenAPI 3.1). Mutually exclusive with Url.
+	Url        string     `json:"url,omitempty" yaml:"url,omitempty"`               // A URL to the license used for the API. Mutually exclusive with Identifier.
+	Extensions Extensions `json:"-" yaml:"-"`                                       // Specification extensions (x- properties) of the object.
 }
 
 // Info provides metadata about the API
 type Info struct {
-	Title          string   `json:"title" yaml:"title"`                             // REQUIRED. The title of the API.
-	Description    string   `json:"description,omitempty" yaml:"description"`       // A short description of the API.
-	TermsOfService string   `json:"termsOfService,omitempty" yaml:"termsOfService"` // A URL to the Terms of Service for the API.
-	Contact        *Contact `json:"contact,omitempty" yaml:"contact"`               // The contact information for the exposed API.
-	License        *License `json:"license,omitempty" yaml:"license"`               // The license information for the exposed API.
-	Version        string   `json:"version" yaml:"version"`                         // REQUIRED. The version of the OpenAPI document.
+	Title          string     `json:"title" yaml:"title"`                                       // REQUIRED. The title of the API.
+	Summary        string     `json:"summary,omitempty" yaml:"summary,omitempty"`               // A short summary of the API (OpenAPI 3.1).
+	Description    string     `json:"description,omitempty" yaml:"description,omitempty"`       // A short description of the API.
+	TermsOfService string     `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"` // A URL to the Terms of Service for the API.
+	Contact        *Contact   `json:"contact,omitempty" yaml:"contact,omitempty"`               // The contact information for the exposed API.
+	License        *License   `json:"license,omitempty" yaml:"license,omitempty"`               // The license information for the exposed API.
+	Version        string     `json:"version" yaml:"version"`                                   // REQUIRED. The version of the OpenAPI document.
+	Extensions     Extensions `json:"-" yaml:"-"`                                               // Specification extensions (x- properties) of the object.
 }
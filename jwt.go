@@ -0,0 +1,426 @@
+package oas
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // Registers SHA-256 for crypto.Hash.
+	_ "crypto/sha512" // Registers SHA-384 and SHA-512 for crypto.Hash.
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Claims are the claims of a verified JSON Web Token.
+type Claims map[string]interface{}
+
+// KeySet holds the keys JSON Web Tokens are verified with, keyed by key ID. Keys are
+// *rsa.PublicKey, *ecdsa.PublicKey or []byte for HMAC secrets. A KeySet is safe for
+// concurrent use.
+type KeySet struct {
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	anonymous []interface{} // The keys without an ID, in the order they were added.
+}
+
+// NewKeySet returns an empty KeySet.
+func NewKeySet() *KeySet {
+	return &KeySet{keys: map[string]interface{}{}}
+}
+
+// Add adds a key with the given ID, replacing any key with the same ID. Keys without an ID
+// are all kept. Tokens without a "kid" header are checked against every key.
+func (k *KeySet) Add(kid string, key interface{}) error {
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, []byte:
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.add(kid, key)
+	return nil
+}
+
+// add adds a key, with k.mu held for writing.
+func (k *KeySet) add(kid string, key interface{}) {
+	if kid == "" {
+		k.anonymous = append(k.anonymous, key)
+		return
+	}
+	k.keys[kid] = key
+}
+
+// ParseJWKS parses a JSON Web Key Set. RSA, EC (P-256, P-384 and P-521) and oct keys are
+// supported; keys of other types are skipped.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("error parsing JWKS: %w", err)
+	}
+	keys := NewKeySet()
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			var n, e []byte
+			if n, err = base64.RawURLEncoding.DecodeString(jwk.N); err == nil {
+				e, err = base64.RawURLEncoding.DecodeString(jwk.E)
+			}
+			if err == nil {
+				key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+			}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				err = fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+			}
+			var x, y []byte
+			if err == nil {
+				if x, err = base64.RawURLEncoding.DecodeString(jwk.X); err == nil {
+					y, err = base64.RawURLEncoding.DecodeString(jwk.Y)
+				}
+			}
+			if err == nil {
+				key = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			}
+		case "oct":
+			key, err = base64.RawURLEncoding.DecodeString(jwk.K)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing key %d of JWKS: %w", i, err)
+		}
+		keys.add(jwk.Kid, key)
+	}
+	return keys, nil
+}
+
+// LoadJWKS reads a JSON Web Key Set from a file.
+func LoadJWKS(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// candidates returns the keys a token with the given key ID may be signed with.
+func (k *KeySet) candidates(kid string) []interface{} {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if kid != "" {
+		if key, ok := k.keys[kid]; ok {
+			return []interface{}{key}
+		}
+		return nil
+	}
+	keys := make([]interface{}, 0, len(k.keys)+len(k.anonymous))
+	for _, id := range sortedKeys(k.keys) {
+		keys = append(keys, k.keys[id])
+	}
+	return append(keys, k.anonymous...)
+}
+
+// JWTOption configures how JSON Web Tokens are verified.
+type JWTOption func(*jwtVerifier)
+
+type jwtVerifier struct {
+	keys       *KeySet
+	issuer     string
+	audience   string
+	leeway     time.Duration
+	scopeClaim string
+	now        func() time.Time
+}
+
+// WithIssuer requires the "iss" claim to equal issuer.
+func WithIssuer(issuer string) JWTOption {
+	return func(v *jwtVerifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires the "aud" claim to contain audience.
+func WithAudience(audience string) JWTOption {
+	return func(v *jwtVerifier) {
+		v.audience = audience
+	}
+}
+
+// WithLeeway allows for clock skew when checking the "exp" and "nbf" claims.
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(v *jwtVerifier) {
+		v.leeway = leeway
+	}
+}
+
+// WithScopeClaim reads the granted scopes from the named claim instead of "scope" or "scp".
+// The claim may hold a space-separated string or an array of strings.
+func WithScopeClaim(name string) JWTOption {
+	return func(v *jwtVerifier) {
+		v.scopeClaim = name
+	}
+}
+
+// WithClock sets the function returning the current time, for tests.
+func WithClock(now func() time.Time) JWTOption {
+	return func(v *jwtVerifier) {
+		v.now = now
+	}
+}
+
+// NewJWTVerifier returns a Verifier of JSON Web Tokens signed with a key of keys using
+// HS256, HS384, HS512, RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384 or ES512.
+// The "exp" and "nbf" claims are checked when present. The principal's Subject is the
+// "sub" claim, its Scopes those the token grants and its Value the token's Claims.
+func NewJWTVerifier(keys *KeySet, opts ...JWTOption) Verifier {
+	v := &jwtVerifier{keys: keys, now: time.Now}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return func(r *http.Request, c *Credentials) (*Principal, error) {
+		claims, err := v.verify(c.Token)
+		if err != nil {
+			return nil, err
+		}
+		subject, _ := claims["sub"].(string)
+		return &Principal{Subject: subject, Scopes: v.scopes(claims), Value: claims}, nil
+	}
+}
+
+// UseJWT registers a JWT verifier for every security scheme of the document of type http
+// with the bearer scheme and the JWT bearer format.
+func (a *Authenticator) UseJWT(keys *KeySet, opts ...JWTOption) {
+	if a.Document.Components == nil {
+		return
+	}
+	verifier := NewJWTVerifier(keys, opts...)
+	for name := range a.Document.Components.SecuritySchemes {
+		scheme, err := a.scheme(name)
+		if err != nil {
+			continue
+		}
+		if scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer") && strings.EqualFold(scheme.BearerFormat, "JWT") {
+			a.Verify(name, verifier)
+		}
+	}
+}
+
+// ClaimsFromContext returns the claims of the JSON Web Token that authenticated the request,
+// as stored by Authenticator.Middleware.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	result, ok := AuthResultFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	for _, name := range sortedKeys(result.Principals) {
+		if claims, ok := result.Principals[name].Value.(Claims); ok {
+			return claims, true
+		}
+	}
+	return nil, false
+}
+
+// verify checks the signature and the time claims of a compact JWS token and returns its claims.
+func (v *jwtVerifier) verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range v.keys.candidates(header.Kid) {
+		if err := verifySignature(header.Alg, key, signed, signature); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("invalid token signature")
+	}
+
+	claims := Claims{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	now := v.now()
+	if exp, ok := numericDate(claims["exp"]); ok && !now.Before(exp.Add(v.leeway)) {
+		return nil, errors.New("token is expired")
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(v.leeway).Before(nbf) {
+		return nil, errors.New("token is not valid yet")
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return nil, fmt.Errorf("token issuer is not '%s'", v.issuer)
+	}
+	if v.audience != "" && !contains(stringList(claims["aud"]), v.audience) {
+		return nil, fmt.Errorf("token audience does not include '%s'", v.audience)
+	}
+	return claims, nil
+}
+
+// scopes returns the scopes granted by claims.
+func (v *jwtVerifier) scopes(claims Claims) []string {
+	if v.scopeClaim != "" {
+		return stringList(claims[v.scopeClaim])
+	}
+	if scope, ok := claims["scope"]; ok {
+		return stringList(scope)
+	}
+	return stringList(claims["scp"])
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// numericDate converts a NumericDate claim to a time.
+func numericDate(claim interface{}) (time.Time, bool) {
+	n, ok := claim.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(f*float64(time.Second))), true
+}
+
+// stringList returns a claim holding a space-separated string or an array of strings as a list.
+func stringList(claim interface{}) []string {
+	switch c := claim.(type) {
+	case string:
+		return strings.Fields(c)
+	case []interface{}:
+		list := make([]string, 0, len(c))
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// verifySignature checks the signature of signed with key using the JWS algorithm alg.
+func verifySignature(alg string, key interface{}, signed, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported algorithm '%s'", alg)
+	}
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported algorithm '%s'", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return errors.New("key is not an HMAC secret")
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	case "RS":
+		public, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key is not an RSA key")
+		}
+		return rsa.VerifyPKCS1v15(public, hash, digest, signature)
+	case "PS":
+		public, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key is not an RSA key")
+		}
+		return rsa.VerifyPSS(public, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	case "ES":
+		public, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key is not an ECDSA key")
+		}
+		bits := public.Curve.Params().BitSize
+		if bits != map[crypto.Hash]int{crypto.SHA256: 256, crypto.SHA384: 384, crypto.SHA512: 521}[hash] {
+			return fmt.Errorf("key curve does not match algorithm '%s'", alg)
+		}
+		size := (bits + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("signature has the wrong length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(public, digest, r, s) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm '%s'", alg)
+}
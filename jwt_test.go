@@ -0,0 +1,175 @@
+package oas
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signToken returns a compact JWS token with the given header and claims, signed by sign.
+func signToken(t *testing.T, header, claims map[string]interface{}, sign func(signed []byte) []byte) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func hmacSigner(secret []byte) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(crypto.SHA256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublic, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("secret")
+	signed := []byte("header.claims")
+	digest := crypto.SHA256.New()
+	digest.Write(signed)
+	sum := digest.Sum(nil)
+
+	rs256, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps256, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, sum, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, ecKey, sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es256 := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	tests := []struct {
+		name      string
+		alg       string
+		key       interface{}
+		signature []byte
+		valid     bool
+	}{
+		{"HS256", "HS256", secret, hmacSigner(secret)(signed), true},
+		{"HS256 with another secret", "HS256", []byte("other"), hmacSigner(secret)(signed), false},
+		{"RS256", "RS256", &rsaKey.PublicKey, rs256, true},
+		{"PS256", "PS256", &rsaKey.PublicKey, ps256, true},
+		{"ES256", "ES256", &ecKey.PublicKey, es256, true},
+		{"ES256 with a truncated signature", "ES256", &ecKey.PublicKey, es256[1:], false},
+		{"none", "none", secret, nil, false},
+		{"none with an RSA key", "none", &rsaKey.PublicKey, nil, false},
+		{"HS256 with an RSA key", "HS256", &rsaKey.PublicKey, hmacSigner(rsaPublic)(signed), false},
+		{"RS256 with an HMAC secret", "RS256", secret, rs256, false},
+		{"ES384 with a P-256 key", "ES384", &ecKey.PublicKey, es256, false},
+		{"lowercase algorithm", "hs256", secret, hmacSigner(secret)(signed), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := verifySignature(test.alg, test.key, signed, test.signature)
+			if (err == nil) != test.valid {
+				t.Errorf("verifySignature(%s) = %v, want valid %t", test.alg, err, test.valid)
+			}
+		})
+	}
+}
+
+func TestJWTTimeClaims(t *testing.T) {
+	secret := []byte("secret")
+	keys := NewKeySet()
+	if err := keys.Add("k1", secret); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		leeway time.Duration
+		err    string
+	}{
+		{"no time claims", map[string]interface{}{}, 0, ""},
+		{"not expired", map[string]interface{}{"exp": now.Unix() + 1}, 0, ""},
+		{"expired now", map[string]interface{}{"exp": now.Unix()}, 0, "token is expired"},
+		{"expired within leeway", map[string]interface{}{"exp": now.Unix() - 30}, time.Minute, ""},
+		{"expired beyond leeway", map[string]interface{}{"exp": now.Unix() - 60}, time.Minute, "token is expired"},
+		{"valid from now", map[string]interface{}{"nbf": now.Unix()}, 0, ""},
+		{"not valid yet", map[string]interface{}{"nbf": now.Unix() + 1}, 0, "token is not valid yet"},
+		{"not valid yet within leeway", map[string]interface{}{"nbf": now.Unix() + 30}, time.Minute, ""},
+		{"not valid yet beyond leeway", map[string]interface{}{"nbf": now.Unix() + 61}, time.Minute, "token is not valid yet"},
+		{"fractional expiry", map[string]interface{}{"exp": float64(now.Unix()) + 0.5}, 0, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := &jwtVerifier{keys: keys, leeway: test.leeway, now: func() time.Time { return now }}
+			token := signToken(t, map[string]interface{}{"alg": "HS256", "kid": "k1"}, test.claims, hmacSigner(secret))
+			_, err := v.verify(token)
+			if test.err == "" && err != nil {
+				t.Errorf("verify() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || err.Error() != test.err) {
+				t.Errorf("verify() = %v, want %s", err, test.err)
+			}
+		})
+	}
+}
+
+func TestParseJWKSWithoutKeyIDs(t *testing.T) {
+	first, second := []byte("first"), []byte("second")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := `{"keys": [
+		{"kty": "oct", "k": "` + base64.RawURLEncoding.EncodeToString(first) + `"},
+		{"kty": "RSA", "n": "` + base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()) + `", "e": "` +
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()) + `"},
+		{"kty": "oct", "k": "` + base64.RawURLEncoding.EncodeToString(second) + `"},
+		{"kty": "oct", "kid": "enc", "use": "enc", "k": "` + base64.RawURLEncoding.EncodeToString(second) + `"}
+	]}`
+	keys, err := ParseJWKS([]byte(jwks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(keys.candidates("")); got != 3 {
+		t.Errorf("len(candidates(\"\")) = %d, want 3", got)
+	}
+
+	v := &jwtVerifier{keys: keys, now: time.Now}
+	for _, secret := range [][]byte{first, second} {
+		token := signToken(t, map[string]interface{}{"alg": "HS256"}, map[string]interface{}{"sub": "ada"}, hmacSigner(secret))
+		if _, err := v.verify(token); err != nil {
+			t.Errorf("verify() with key %q = %v, want no error", secret, err)
+		}
+	}
+	token := signToken(t, map[string]interface{}{"alg": "HS256", "kid": "missing"}, map[string]interface{}{}, hmacSigner(first))
+	if _, err := v.verify(token); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("verify() with an unknown kid = %v, want a signature error", err)
+	}
+}
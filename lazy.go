@@ -0,0 +1,360 @@
+package oas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// LazyOpenAPI is a document whose Paths and Components.Schemas entries are decoded on first
+// access. It is returned by LoadLazy and is safe for concurrent use. Endpoint returns a
+// document holding a single path and the schemas it needs, which can be validated against
+// without decoding the rest.
+type LazyOpenAPI struct {
+	mu       sync.Mutex
+	skeleton *OpenAPI // The document without paths and schemas.
+	source   io.ReaderAt
+	base     int64 // The offset of the document within source.
+	paths    map[string]*lazyEntry
+	schemas  map[string]*lazyEntry
+}
+
+// lazyEntry is an undecoded entry, held either as raw bytes or as a section of the source.
+// A section may start with the ':' that separates the value from its key.
+type lazyEntry struct {
+	raw    json.RawMessage
+	offset int64
+	length int64
+	path   *Path
+	schema *Schema
+}
+
+// LoadLazy reads a JSON document token by token, keeping every path and schema undecoded
+// until it is accessed. When r is an io.ReaderAt, such as an *os.File, only the offsets of
+// the entries are kept and r must stay open and unchanged while the document is used;
+// otherwise their raw bytes are. YAML documents cannot be streamed: they are decoded fully
+// and only wrapped, so loading them lazily saves no time or memory.
+func LoadLazy(r io.Reader) (*LazyOpenAPI, error) {
+	l := &LazyOpenAPI{paths: map[string]*lazyEntry{}, schemas: map[string]*lazyEntry{}}
+	if at, ok := r.(io.ReaderAt); ok {
+		l.source = at
+		if seeker, ok := r.(io.Seeker); ok {
+			base, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			l.base = base
+		}
+	}
+
+	buffered := bufio.NewReader(r)
+	if !startsWithObject(buffered) {
+		doc, err := Load(buffered)
+		if err != nil {
+			return nil, err
+		}
+		l.source = nil
+		for name, path := range doc.Paths {
+			l.paths[name] = &lazyEntry{path: path}
+		}
+		doc.Paths = nil
+		if doc.Components != nil {
+			for name, schema := range doc.Components.Schemas {
+				l.schemas[name] = &lazyEntry{schema: schema}
+			}
+			components := *doc.Components
+			components.Schemas = nil
+			doc.Components = &components
+		}
+		l.skeleton = doc
+		return l, nil
+	}
+
+	dec := json.NewDecoder(buffered)
+	rest := map[string]json.RawMessage{}
+	err := decodeObject(dec, func(key string) error {
+		switch key {
+		case "paths":
+			return decodeObject(dec, func(name string) error {
+				entry, err := l.entry(dec)
+				l.paths[name] = entry
+				return err
+			})
+		case "components":
+			components := map[string]json.RawMessage{}
+			err := decodeObject(dec, func(kind string) error {
+				if kind != "schemas" {
+					var raw json.RawMessage
+					err := dec.Decode(&raw)
+					components[kind] = raw
+					return err
+				}
+				return decodeObject(dec, func(name string) error {
+					entry, err := l.entry(dec)
+					l.schemas[name] = entry
+					return err
+				})
+			})
+			if err != nil {
+				return err
+			}
+			raw, err := json.Marshal(components)
+			rest[key] = raw
+			return err
+		}
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		rest[key] = raw
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decoding document: %w", err)
+	}
+
+	raw, err := json.Marshal(rest)
+	if err != nil {
+		return nil, err
+	}
+	l.skeleton = &OpenAPI{}
+	if err := json.Unmarshal(raw, l.skeleton); err != nil {
+		return nil, err
+	}
+	if l.skeleton.OpenAPIVersion == "" && rest["swagger"] != nil {
+		return nil, errSwagger
+	}
+	return l, nil
+}
+
+// startsWithObject reports whether the first non-space byte of r opens a JSON object.
+func startsWithObject(r *bufio.Reader) bool {
+	for n := 1; ; n++ {
+		peeked, err := r.Peek(n)
+		if err != nil {
+			return false
+		}
+		switch c := peeked[n-1]; c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		default:
+			return c == '{'
+		}
+	}
+}
+
+// decodeObject reads a JSON object from dec and calls entry for every key, which must consume the value.
+func decodeObject(dec *json.Decoder, entry func(key string) error) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an object, got %v", token)
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := entry(token.(string)); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// entry reads the next value of dec. When the source can be re-read, the value is skipped
+// token by token and only its offsets are kept; otherwise its bytes are.
+func (l *LazyOpenAPI) entry(dec *json.Decoder) (*lazyEntry, error) {
+	if l.source == nil {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		return &lazyEntry{raw: raw}, nil
+	}
+	start := dec.InputOffset()
+	if err := skipValue(dec); err != nil {
+		return nil, err
+	}
+	return &lazyEntry{offset: l.base + start, length: dec.InputOffset() - start}, nil
+}
+
+// skipValue reads the next value of dec without keeping it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// bytes returns the undecoded JSON of an entry.
+func (l *LazyOpenAPI) bytes(e *lazyEntry) ([]byte, error) {
+	if e.raw != nil {
+		return e.raw, nil
+	}
+	data := make([]byte, e.length)
+	if _, err := l.source.ReadAt(data, e.offset); err != nil {
+		return nil, err
+	}
+	return bytes.TrimLeft(data, " \t\r\n:"), nil
+}
+
+// Skeleton returns the document without its Paths and Components.Schemas entries.
+func (l *LazyOpenAPI) Skeleton() *OpenAPI {
+	return l.skeleton
+}
+
+// PathNames returns the sorted names of the paths of the document.
+func (l *LazyOpenAPI) PathNames() []string {
+	return sortedKeys(l.paths)
+}
+
+// SchemaNames returns the sorted names of the schemas of the document's components.
+func (l *LazyOpenAPI) SchemaNames() []string {
+	return sortedKeys(l.schemas)
+}
+
+// Path decodes the path with the given name on first access and returns it, or nil when
+// the document has no such path.
+func (l *LazyOpenAPI) Path(name string) (*Path, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := l.paths[name]
+	if e == nil {
+		return nil, nil
+	}
+	if e.path != nil {
+		return e.path, nil
+	}
+	data, err := l.bytes(e)
+	if err != nil {
+		return nil, err
+	}
+	path := &Path{}
+	if err := json.Unmarshal(data, path); err != nil {
+		return nil, fmt.Errorf("error decoding path '%s': %w", name, err)
+	}
+	e.path, e.raw = path, nil
+	return path, nil
+}
+
+// Schema decodes the schema component with the given name on first access and returns it,
+// or nil when the document has no such schema.
+func (l *LazyOpenAPI) Schema(name string) (*Schema, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := l.schemas[name]
+	if e == nil {
+		return nil, nil
+	}
+	if e.schema != nil {
+		return e.schema, nil
+	}
+	data, err := l.bytes(e)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("error decoding schema '%s': %w", name, err)
+	}
+	e.schema, e.raw = schema, nil
+	return schema, nil
+}
+
+// OpenAPI decodes every remaining path and schema and returns the complete document.
+// The skeleton is shared with the returned document.
+func (l *LazyOpenAPI) OpenAPI() (*OpenAPI, error) {
+	doc := *l.skeleton
+	doc.Paths = make(map[string]*Path, len(l.paths))
+	for _, name := range l.PathNames() {
+		path, err := l.Path(name)
+		if err != nil {
+			return nil, err
+		}
+		doc.Paths[name] = path
+	}
+	if len(l.schemas) > 0 {
+		components := Components{}
+		if doc.Components != nil {
+			components = *doc.Components
+		}
+		components.Schemas = make(map[string]*Schema, len(l.schemas))
+		for _, name := range l.SchemaNames() {
+			schema, err := l.Schema(name)
+			if err != nil {
+				return nil, err
+			}
+			components.Schemas[name] = schema
+		}
+		doc.Components = &components
+	}
+	return &doc, nil
+}
+
+// Endpoint returns a document with the skeleton, the path with the given name and only the
+// schema components that path references, directly or through other components. Paths and
+// schemas it does not need stay undecoded. It returns nil when the document has no such path.
+func (l *LazyOpenAPI) Endpoint(name string) (*OpenAPI, error) {
+	path, err := l.Path(name)
+	if path == nil || err != nil {
+		return nil, err
+	}
+	doc := *l.skeleton
+	doc.Paths = map[string]*Path{name: path}
+	components := Components{}
+	if doc.Components != nil {
+		components = *doc.Components
+	}
+	components.Schemas = map[string]*Schema{}
+	doc.Components = &components
+
+	// Each pass decodes the schemas referenced by those decoded before.
+	for added := true; added; {
+		added = false
+		var refs []string
+		Walk(&doc, Visitor{Enter: func(cursor *Cursor) WalkAction {
+			if s, ok := cursor.Value.(*Schema); ok && s.Ref != "" {
+				refs = append(refs, s.Ref)
+			}
+			return WalkContinue
+		}})
+		for _, ref := range refs {
+			rest, ok := strings.CutPrefix(ref, "#/components/schemas/")
+			if !ok {
+				continue
+			}
+			schemaName, _, _ := strings.Cut(rest, "/")
+			schemaName = pointerUnescape(schemaName)
+			if components.Schemas[schemaName] != nil {
+				continue
+			}
+			schema, err := l.Schema(schemaName)
+			if err != nil {
+				return nil, err
+			}
+			if schema != nil {
+				components.Schemas[schemaName] = schema
+				added = true
+			}
+		}
+	}
+	return &doc, nil
+}
@@ -0,0 +1,122 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const lazyDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Pets", "version": "1"},
+	"paths": {
+		"/pets": {"get": {"responses": {"200": {"description": "pets", "content": {"application/json": {"schema": {
+			"type": "array", "items": {"$ref": "#/components/schemas/Pet"}
+		}}}}}}},
+		"/users": {"get": {"responses": {"200": {"description": "users", "content": {"application/json": {"schema": {
+			"$ref": "#/components/schemas/User"
+		}}}}}}}
+	},
+	"components": {
+		"schemas": {
+			"Pet": {"type": "object", "properties": {"owner": {"$ref": "#/components/schemas/Owner"}, "tag": {"$ref": "#/components/schemas/Tag/properties/name"}}},
+			"Owner": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"Tag": {"type": "object", "properties": {"name": {"type": "string"}}},
+			"User": {"type": "object"}
+		},
+		"responses": {"NotFound": {"description": "not found"}}
+	}
+}`
+
+// hiddenReaderAt hides the io.ReaderAt of a reader, so that LoadLazy keeps raw bytes.
+type hiddenReaderAt struct {
+	io.Reader
+}
+
+func TestLoadLazy(t *testing.T) {
+	var yamlDocument interface{}
+	if err := json.Unmarshal([]byte(lazyDocument), &yamlDocument); err != nil {
+		t.Fatal(err)
+	}
+	yamlData, err := yaml.Marshal(yamlDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(filepath.Join(t.TempDir(), "pets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// The document starts after a prefix the file has already been read past.
+	if _, err := file.WriteString("prefix" + lazyDocument); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		reader func() io.Reader
+	}{
+		{name: "raw bytes", reader: func() io.Reader { return hiddenReaderAt{strings.NewReader(lazyDocument)} }},
+		{name: "reader at", reader: func() io.Reader { return bytes.NewReader([]byte(lazyDocument)) }},
+		{name: "file at an offset", reader: func() io.Reader {
+			if _, err := file.Seek(int64(len("prefix")), io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			return file
+		}},
+		{name: "YAML", reader: func() io.Reader { return bytes.NewReader(yamlData) }},
+	}
+	want, err := LoadJSON([]byte(lazyDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, err := LoadLazy(test.reader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := l.PathNames(); !reflect.DeepEqual(got, []string{"/pets", "/users"}) {
+				t.Errorf("PathNames() = %v", got)
+			}
+			if got := l.SchemaNames(); !reflect.DeepEqual(got, []string{"Owner", "Pet", "Tag", "User"}) {
+				t.Errorf("SchemaNames() = %v", got)
+			}
+			if skeleton := l.Skeleton(); skeleton.Paths != nil || skeleton.Components.Schemas != nil || skeleton.Components.Responses["NotFound"] == nil {
+				t.Errorf("Skeleton() = %+v, want the document without paths and schemas", skeleton)
+			}
+			if missing, err := l.Path("/missing"); missing != nil || err != nil {
+				t.Errorf("Path() of a missing path = %v, %v", missing, err)
+			}
+
+			endpoint, err := l.Endpoint("/pets")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sortedKeys(endpoint.Components.Schemas); !reflect.DeepEqual(got, []string{"Owner", "Pet", "Tag"}) {
+				t.Errorf("Endpoint() schemas = %v, want those /pets references", got)
+			}
+			if v, err := Compile(endpoint.Paths["/pets"].Get.Responses["200"].Content["application/json"].Schema, WithComponents(endpoint.Components)); err != nil {
+				t.Error(err)
+			} else if err := v.Validate([]interface{}{map[string]interface{}{"tag": 1}}, false, false); err == nil {
+				t.Error("Validate() = nil, want the referenced tag schema to be checked")
+			}
+
+			doc, err := l.OpenAPI()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(doc)
+			wanted, _ := json.Marshal(want)
+			if string(got) != string(wanted) {
+				t.Errorf("OpenAPI() =\n%s\nwant\n%s", got, wanted)
+			}
+		})
+	}
+}
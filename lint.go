@@ -0,0 +1,475 @@
+package oas
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is the level at which a lint rule reports its findings.
+type Severity string
+
+const (
+	SeverityError   Severity = "error" // The finding must be fixed.
+	SeverityWarning Severity = "warn"  // The finding should be fixed.
+	SeverityInfo    Severity = "info"  // The finding is informational only.
+	SeverityOff     Severity = "off"   // The rule is disabled.
+)
+
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return true
+	}
+	return false
+}
+
+// LintIssue is a single finding reported by a lint rule.
+type LintIssue struct {
+	Rule     string   // The name of the rule that reported the issue.
+	Severity Severity // The severity the rule was configured with.
+	Pointer  string   // The JSON Pointer of the offending node.
+	Position Position // The source position of the offending node, when known.
+	Message  string   // A human readable description of the issue.
+}
+
+func (i LintIssue) String() string {
+	if i.Position.IsValid() {
+		return fmt.Sprintf("%s: %s: %s [%s] %s", i.Position, i.Pointer, i.Severity, i.Rule, i.Message)
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", i.Pointer, i.Severity, i.Rule, i.Message)
+}
+
+// LintIssues is the list of issues reported by a Linter.
+type LintIssues []LintIssue
+
+// HasErrors reports whether any of the issues has SeverityError.
+func (l LintIssues) HasErrors() bool {
+	for _, i := range l {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// LintVisitor holds the callbacks a rule wants invoked while the document is walked.
+// Callbacks that are nil are skipped.
+type LintVisitor struct {
+	Document       func(c *LintContext, doc *OpenAPI)
+	Tag            func(c *LintContext, tag *Tag)
+	Path           func(c *LintContext, path string, item *Path)
+	Operation      func(c *LintContext, path, method string, op *Operation)
+	Parameter      func(c *LintContext, p *Parameter)
+	RequestBody    func(c *LintContext, rb *RequestBody)
+	Response       func(c *LintContext, code string, r *Response)
+	Header         func(c *LintContext, name string, h *Header)
+	MediaType      func(c *LintContext, name string, m *MediaType)
+	Example        func(c *LintContext, name string, e *Example)
+	Link           func(c *LintContext, name string, l *Link)
+	Callback       func(c *LintContext, name string, cb *Callback)
+	SecurityScheme func(c *LintContext, name string, ss *SecurityScheme)
+	Schema         func(c *LintContext, s *Schema)
+}
+
+// LintContext is passed to every LintVisitor callback.
+type LintContext struct {
+	Document *OpenAPI // The document being linted.
+	Pointer  string   // The JSON Pointer of the node being visited.
+
+	rule   *LintRule
+	issues *LintIssues
+}
+
+// Report records an issue at the node being visited.
+func (c *LintContext) Report(format string, args ...interface{}) {
+	c.ReportAt(c.Pointer, format, args...)
+}
+
+// ReportAt records an issue at the given JSON Pointer.
+func (c *LintContext) ReportAt(pointer string, format string, args ...interface{}) {
+	position, _ := c.Document.Position(pointer)
+	*c.issues = append(*c.issues, LintIssue{
+		Rule:     c.rule.Name,
+		Severity: c.rule.Severity,
+		Pointer:  pointer,
+		Position: position,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// LintRule is a named check over an OpenAPI document.
+type LintRule struct {
+	Name        string      // REQUIRED. The unique name used to configure the rule.
+	Description string      // A short description of what the rule enforces.
+	Severity    Severity    // The default severity of the rule.
+	Visitor     LintVisitor // The callbacks implementing the rule.
+}
+
+// LintConfig configures the severity of lint rules by name.
+type LintConfig struct {
+	Rules map[string]Severity `json:"rules" yaml:"rules"`
+}
+
+// LoadLintConfig parses a YAML (or JSON) lint configuration such as:
+//
+//	rules:
+//	  operation-operationId: error
+//	  paths-kebab-case: off
+func LoadLintConfig(bytes []byte) (*LintConfig, error) {
+	config := &LintConfig{}
+	if err := yaml.Unmarshal(bytes, config); err != nil {
+		return nil, err
+	}
+	for name, severity := range config.Rules {
+		if !severity.valid() {
+			return nil, fmt.Errorf("rule '%s': unknown severity '%s'", name, severity)
+		}
+	}
+	return config, nil
+}
+
+// Linter checks OpenAPI documents against a set of rules.
+type Linter struct {
+	rules []*LintRule
+}
+
+// NewLinter returns a Linter running the built-in rules followed by any custom rules.
+// The config, which may be nil, overrides rule severities; naming an unknown rule is an error.
+func NewLinter(config *LintConfig, custom ...*LintRule) (*Linter, error) {
+	var rules []*LintRule
+	for _, r := range append(DefaultLintRules(), custom...) {
+		r := *r
+		rules = append(rules, &r)
+	}
+	byName := make(map[string]*LintRule, len(rules))
+	for _, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("lint rule has no name")
+		}
+		if _, ok := byName[r.Name]; ok {
+			return nil, fmt.Errorf("duplicate lint rule '%s'", r.Name)
+		}
+		if r.Severity == "" {
+			r.Severity = SeverityWarning
+		}
+		byName[r.Name] = r
+	}
+	if config != nil {
+		for name, severity := range config.Rules {
+			r, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown lint rule '%s'", name)
+			}
+			if !severity.valid() {
+				return nil, fmt.Errorf("rule '%s': unknown severity '%s'", name, severity)
+			}
+			r.Severity = severity
+		}
+	}
+	linter := &Linter{}
+	for _, r := range rules {
+		if r.Severity != SeverityOff {
+			linter.rules = append(linter.rules, r)
+		}
+	}
+	return linter, nil
+}
+
+// Lint runs every enabled rule over the document and returns the issues sorted by pointer.
+func (l *Linter) Lint(doc *OpenAPI) LintIssues {
+	var issues LintIssues
+	Walk(doc, Visitor{Enter: func(c *Cursor) WalkAction {
+		for _, r := range l.rules {
+			r.Visitor.visit(&LintContext{Document: doc, Pointer: c.Pointer, rule: r, issues: &issues}, c)
+		}
+		return WalkContinue
+	}})
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Pointer != issues[j].Pointer {
+			return issues[i].Pointer < issues[j].Pointer
+		}
+		return issues[i].Rule < issues[j].Rule
+	})
+	return issues
+}
+
+var (
+	camelCaseRegex = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	kebabCaseRegex = regexp.MustCompile(`^[a-z0-9]+(?:[-.][a-z0-9]+)*$`)
+	templateRegex  = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+// DefaultLintRules returns a fresh copy of the built-in rules.
+func DefaultLintRules() []*LintRule {
+	return []*LintRule{
+		{
+			Name:        "operation-operationId",
+			Description: "Every operation has an operationId.",
+			Severity:    SeverityError,
+			Visitor: LintVisitor{Operation: func(c *LintContext, path, method string, op *Operation) {
+				if op.OperationID == "" {
+					c.Report("operation is missing an operationId")
+				}
+			}},
+		},
+		{
+			Name:        "operation-operationId-camel-case",
+			Description: "Every operationId is camelCase.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Operation: func(c *LintContext, path, method string, op *Operation) {
+				if op.OperationID != "" && !camelCaseRegex.MatchString(op.OperationID) {
+					c.ReportAt(c.Pointer+"/operationId", "operationId '%s' is not camelCase", op.OperationID)
+				}
+			}},
+		},
+		{
+			Name:        "operation-tags",
+			Description: "Every operation has a tag that is declared in the document tags.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Operation: func(c *LintContext, path, method string, op *Operation) {
+				if len(op.Tags) == 0 {
+					c.Report("operation has no tags")
+					return
+				}
+				for i, name := range op.Tags {
+					declared := false
+					for _, tag := range c.Document.Tags {
+						if tag != nil && tag.Name == name {
+							declared = true
+							break
+						}
+					}
+					if !declared {
+						c.ReportAt(fmt.Sprintf("%s/tags/%d", c.Pointer, i), "tag '%s' is not declared in the document tags", name)
+					}
+				}
+			}},
+		},
+		{
+			Name:        "info-description",
+			Description: "The info object has a description.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Document: func(c *LintContext, doc *OpenAPI) {
+				if doc.Info != nil && doc.Info.Description == "" {
+					c.ReportAt("/info", "info is missing a description")
+				}
+			}},
+		},
+		{
+			Name:        "tag-description",
+			Description: "Every declared tag has a description.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Tag: func(c *LintContext, tag *Tag) {
+				if tag.Description == "" {
+					c.Report("tag '%s' is missing a description", tag.Name)
+				}
+			}},
+		},
+		{
+			Name:        "operation-description",
+			Description: "Every operation has a description.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Operation: func(c *LintContext, path, method string, op *Operation) {
+				if op.Description == "" {
+					c.Report("operation is missing a description")
+				}
+			}},
+		},
+		{
+			Name:        "parameter-description",
+			Description: "Every parameter has a description.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Parameter: func(c *LintContext, p *Parameter) {
+				if p.Ref == "" && p.Description == "" {
+					c.Report("parameter '%s' is missing a description", p.Name)
+				}
+			}},
+		},
+		{
+			Name:        "no-unused-components",
+			Description: "Every component is reachable from the paths or webhooks of the document.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Document: func(c *LintContext, doc *OpenAPI) {
+				for _, ref := range doc.UnusedComponents() {
+					c.ReportAt(ref.Pointer(), "component is never referenced")
+				}
+				for _, ref := range doc.UnusedSecuritySchemes() {
+					c.ReportAt(ref.Pointer(), "security scheme is never required")
+				}
+			}},
+		},
+		{
+			Name:        "paths-kebab-case",
+			Description: "Every path segment is kebab-case.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Path: func(c *LintContext, path string, item *Path) {
+				for _, segment := range strings.Split(path, "/") {
+					segment = templateRegex.ReplaceAllString(segment, "")
+					if segment != "" && !kebabCaseRegex.MatchString(segment) {
+						c.Report("path segment '%s' is not kebab-case", segment)
+						return
+					}
+				}
+			}},
+		},
+		{
+			Name:        "operation-4xx-response",
+			Description: "Every operation declares at least one 4XX response.",
+			Severity:    SeverityWarning,
+			Visitor: LintVisitor{Operation: func(c *LintContext, path, method string, op *Operation) {
+				for code := range op.Responses {
+					if len(code) == 3 && code[0] == '4' {
+						return
+					}
+				}
+				c.ReportAt(c.Pointer+"/responses", "operation has no 4XX response")
+			}},
+		},
+		{
+			Name:        "example-matches-schema",
+			Description: "Every example validates against its schema.",
+			Severity:    SeverityError,
+			Visitor: LintVisitor{
+				Parameter: func(c *LintContext, p *Parameter) {
+					checkExamples(c, p.Schema, p.Example, p.Examples)
+				},
+				Header: func(c *LintContext, name string, h *Header) {
+					checkExamples(c, h.Schema, h.Example, h.Examples)
+				},
+				MediaType: func(c *LintContext, name string, m *MediaType) {
+					checkExamples(c, m.Schema, m.Example, m.Examples)
+				},
+				Schema: func(c *LintContext, s *Schema) {
+					checkExample(c, s, s.Example, c.Pointer+"/example")
+				},
+			},
+		},
+	}
+}
+
+// checkExamples validates the example and examples of a parameter, header or media type.
+func checkExamples(c *LintContext, s *Schema, example interface{}, examples map[string]*Example) {
+	checkExample(c, s, example, c.Pointer+"/example")
+	for _, name := range sortedKeys(examples) {
+		e := examples[name]
+		pointer := c.Pointer + "/examples/" + pointerEscape(name)
+		if e != nil && e.Ref != "" && c.Document.Components != nil {
+			e = c.Document.Components.Examples[getComponentName(e.Ref)]
+		}
+		if e != nil {
+			checkExample(c, s, e.Value, pointer+"/value")
+		}
+	}
+}
+
+func checkExample(c *LintContext, s *Schema, example interface{}, pointer string) {
+	s = resolveSchema(s, c.Document.Components)
+	if s == nil || example == nil || len(s.Type) == 0 {
+		return
+	}
+	v, err := Compile(s, WithComponents(c.Document.Components))
+	if err != nil {
+		c.ReportAt(pointer, "example cannot be checked: %v", err)
+		return
+	}
+	if err := v.Validate(example, false, true); err != nil {
+		c.ReportAt(pointer, "example does not match schema: %v", strings.TrimSpace(err.Error()))
+	}
+}
+
+// resolveSchema follows $ref fields on s without logging missing references.
+func resolveSchema(s *Schema, components *Components) *Schema {
+	for i := 0; s != nil && s.Ref != "" && i < 32; i++ {
+		if components == nil {
+			return nil
+		}
+		s = components.Schemas[getComponentName(s.Ref)]
+	}
+	return s
+}
+
+// visit calls the callback of v matching the type of the node under the cursor.
+func (v *LintVisitor) visit(lc *LintContext, c *Cursor) {
+	switch n := c.Value.(type) {
+	case *OpenAPI:
+		if v.Document != nil {
+			v.Document(lc, n)
+		}
+	case *Tag:
+		if v.Tag != nil {
+			v.Tag(lc, n)
+		}
+	case *Path:
+		if v.Path != nil {
+			v.Path(lc, c.Key, n)
+		}
+	case *Operation:
+		if v.Operation != nil {
+			v.Operation(lc, c.Parent.Key, c.Key, n)
+		}
+	case *Parameter:
+		if v.Parameter != nil {
+			v.Parameter(lc, n)
+		}
+	case *RequestBody:
+		if v.RequestBody != nil {
+			v.RequestBody(lc, n)
+		}
+	case *Response:
+		if v.Response != nil {
+			v.Response(lc, c.Key, n)
+		}
+	case *Header:
+		if v.Header != nil {
+			v.Header(lc, c.Key, n)
+		}
+	case *MediaType:
+		if v.MediaType != nil {
+			v.MediaType(lc, c.Key, n)
+		}
+	case *Example:
+		if v.Example != nil {
+			v.Example(lc, c.Key, n)
+		}
+	case *Link:
+		if v.Link != nil {
+			v.Link(lc, c.Key, n)
+		}
+	case *Callback:
+		if v.Callback != nil {
+			v.Callback(lc, c.Key, n)
+		}
+	case *SecurityScheme:
+		if v.SecurityScheme != nil {
+			v.SecurityScheme(lc, c.Key, n)
+		}
+	case *Schema:
+		if v.Schema != nil {
+			v.Schema(lc, n)
+		}
+	}
+}
+
+// pointerEscape escapes a JSON Pointer reference token as described in RFC 6901.
+func pointerEscape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// pointerUnescape reverses pointerEscape.
+func pointerUnescape(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
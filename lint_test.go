@@ -0,0 +1,149 @@
+package oas
+
+import (
+	"strings"
+	"testing"
+)
+
+// lintDocument is a document none of the built-in rules reports; the tests replace parts of it.
+const lintDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Pets", "version": "1", "description": "Pet store."},
+	"tags": [{"name": "pets", "description": "Pets."}],
+	"paths": {"/pet-owners/{ownerId}": {"get": {
+		"operationId": "listPets",
+		"description": "Lists pets.",
+		"tags": ["pets"],
+		"parameters": [{"name": "ownerId", "in": "path", "required": true, "description": "Owner.", "schema": {"type": "integer"}, "example": 1}],
+		"responses": {
+			"200": {"description": "pets", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+			"404": {"description": "not found"}
+		}
+	}}},
+	"components": {"schemas": {"Pet": {"type": "object", "properties": {"name": {"type": "string", "example": "Rex"}}}}}
+}`
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace []string // Pairs of text of lintDocument and its replacement.
+		config  string
+		want    []string // The issues reported, as "rule pointer".
+	}{
+		{name: "clean document"},
+		{
+			name:    "missing operationId",
+			replace: []string{`"operationId": "listPets",`, ``},
+			want:    []string{"operation-operationId /paths/~1pet-owners~1{ownerId}/get"},
+		},
+		{
+			name:    "operationId not camelCase",
+			replace: []string{`"listPets"`, `"list_pets"`},
+			want:    []string{"operation-operationId-camel-case /paths/~1pet-owners~1{ownerId}/get/operationId"},
+		},
+		{
+			name:    "undeclared tag",
+			replace: []string{`"tags": ["pets"]`, `"tags": ["pets", "owners"]`},
+			want:    []string{"operation-tags /paths/~1pet-owners~1{ownerId}/get/tags/1"},
+		},
+		{
+			name:    "missing descriptions",
+			replace: []string{`"description": "Pet store."`, `"description": ""`, `"description": "Pets."`, `"description": ""`, `"description": "Owner.", `, ``},
+			want: []string{
+				"info-description /info",
+				"parameter-description /paths/~1pet-owners~1{ownerId}/get/parameters/0",
+				"tag-description /tags/0",
+			},
+		},
+		{
+			name:    "unused component",
+			replace: []string{`"$ref": "#/components/schemas/Pet"`, `"type": "object"`},
+			want:    []string{"no-unused-components /components/schemas/Pet"},
+		},
+		{
+			name:    "path not kebab-case",
+			replace: []string{`/pet-owners/`, `/petOwners/`},
+			want:    []string{"paths-kebab-case /paths/~1petOwners~1{ownerId}"},
+		},
+		{
+			name:    "no 4XX response",
+			replace: []string{`"404"`, `"500"`},
+			want:    []string{"operation-4xx-response /paths/~1pet-owners~1{ownerId}/get/responses"},
+		},
+		{
+			name:    "examples not matching their schema",
+			replace: []string{`"example": 1`, `"example": "one"`, `"example": "Rex"`, `"example": 7`},
+			want: []string{
+				"example-matches-schema /components/schemas/Pet/properties/name/example",
+				"example-matches-schema /paths/~1pet-owners~1{ownerId}/get/parameters/0/example",
+			},
+		},
+		{
+			name:    "rule turned off",
+			replace: []string{`"404"`, `"500"`},
+			config:  "rules:\n  operation-4xx-response: off\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := strings.NewReplacer(test.replace...).Replace(lintDocument)
+			doc, err := LoadJSON([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var config *LintConfig
+			if test.config != "" {
+				if config, err = LoadLintConfig([]byte(test.config)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			linter, err := NewLinter(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, issue := range linter.Lint(doc) {
+				got = append(got, issue.Rule+" "+issue.Pointer)
+			}
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("Lint() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLintCustomRule(t *testing.T) {
+	doc, err := LoadJSON([]byte(lintDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := &LintRule{Name: "no-get", Severity: SeverityError, Visitor: LintVisitor{
+		Operation: func(c *LintContext, path, method string, op *Operation) {
+			if method == "get" {
+				c.Report("GET %s is not allowed", path)
+			}
+		},
+	}}
+	linter, err := NewLinter(nil, rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := linter.Lint(doc)
+	if len(issues) != 1 || issues[0].Message != "GET /pet-owners/{ownerId} is not allowed" || !issues.HasErrors() {
+		t.Errorf("Lint() = %v, want the custom rule's error", issues)
+	}
+	if !issues[0].Position.IsValid() {
+		t.Errorf("issue position = %v, want the position of the operation", issues[0].Position)
+	}
+
+	for _, config := range []string{"rules:\n  no-such-rule: error\n", "rules:\n  no-get: fatal\n"} {
+		if parsed, err := LoadLintConfig([]byte(config)); err == nil {
+			if _, err := NewLinter(parsed, rule); err == nil {
+				t.Errorf("NewLinter() with %q = nil error, want an error", config)
+			}
+		}
+	}
+	if _, err := NewLinter(nil, rule, rule); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("NewLinter() with a rule twice = %v, want a duplicate rule error", err)
+	}
+}
@@ -0,0 +1,283 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadOption configures how a document is loaded.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	file   string
+	strict bool
+}
+
+// Strict rejects documents containing fields the specification does not define, such as a
+// misspelled "requried". Specification extensions (x- properties) are always allowed.
+func Strict() LoadOption {
+	return func(o *loadOptions) {
+		o.strict = true
+	}
+}
+
+// WithFileName sets the file name reported in positions and errors.
+func WithFileName(name string) LoadOption {
+	return func(o *loadOptions) {
+		o.file = name
+	}
+}
+
+var errSwagger = errors.New("document is Swagger 2.0; use NewOpenAPIFromSwagger to convert it")
+
+// LoadJSON parses a JSON document.
+func LoadJSON(data []byte, opts ...LoadOption) (*OpenAPI, error) {
+	return load(data, "json", opts)
+}
+
+// LoadYAML parses a YAML document.
+func LoadYAML(data []byte, opts ...LoadOption) (*OpenAPI, error) {
+	return load(data, "yaml", opts)
+}
+
+// LoadFile reads and parses a document. The format is taken from the extension of the file,
+// ".json", ".yaml" or ".yml", and otherwise detected from its content.
+func LoadFile(path string, opts ...LoadOption) (*OpenAPI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]LoadOption{WithFileName(path)}, opts...)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return load(data, "json", opts)
+	case ".yaml", ".yml":
+		return load(data, "yaml", opts)
+	}
+	return load(data, sniffFormat(data), opts)
+}
+
+// Load reads and parses a document, detecting its format from the content.
+func Load(r io.Reader, opts ...LoadOption) (*OpenAPI, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return load(data, sniffFormat(data), opts)
+}
+
+// Marshal encodes the document as indented JSON.
+func Marshal(o *OpenAPI) ([]byte, error) {
+	return json.MarshalIndent(o, "", "  ")
+}
+
+// MarshalYAML encodes the document as YAML.
+func MarshalYAML(o *OpenAPI) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(o); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sniffFormat returns "json" when data starts with an object and "yaml" otherwise.
+func sniffFormat(data []byte) string {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return "json"
+	}
+	return "yaml"
+}
+
+func load(data []byte, format string, opts []LoadOption) (*OpenAPI, error) {
+	options := &loadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	openAPI := &OpenAPI{}
+	node := &yaml.Node{}
+	if format == "json" {
+		if err := json.Unmarshal(data, openAPI); err != nil {
+			return nil, jsonParseError(data, err, options.file)
+		}
+		// JSON is a subset of YAML, so the node tree provides positions for both formats.
+		if err := yaml.Unmarshal(data, node); err != nil {
+			node = &yaml.Node{}
+		}
+	} else {
+		// The document is parsed once; the node tree is decoded and then provides positions.
+		if err := yaml.Unmarshal(data, node); err != nil {
+			return nil, yamlParseError(err, options.file)
+		}
+		if err := node.Decode(openAPI); err != nil {
+			return nil, yamlParseError(err, options.file)
+		}
+	}
+
+	if openAPI.OpenAPIVersion == "" && declaresSwagger(node, data) {
+		return nil, errSwagger
+	}
+	openAPI.recordPositions(node, options.file)
+	if options.strict && len(node.Content) > 0 {
+		var errs []error
+		unknownFields(node.Content[0], reflect.TypeOf(openAPI), "", options.file, &errs)
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+	}
+	return openAPI, nil
+}
+
+// declaresSwagger reports whether the document has a swagger field, reading it from the
+// parsed node tree when there is one.
+func declaresSwagger(node *yaml.Node, data []byte) bool {
+	if len(node.Content) == 0 {
+		return isSwagger(data)
+	}
+	return yamlHasKey(node.Content[0], "swagger")
+}
+
+// jsonParseError converts an encoding/json error to a ParseError located at its byte offset.
+func jsonParseError(data []byte, err error, file string) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+	if offset <= 0 || offset > int64(len(data)) {
+		return &ParseError{Position: Position{File: file}, Err: err}
+	}
+	// The offset is just past the offending byte.
+	before := data[:offset-1]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return &ParseError{Position: Position{File: file, Line: line, Column: column}, Err: err}
+}
+
+var yamlLineRegex = regexp.MustCompile(`line (\d+): `)
+
+// yamlParseError converts a gopkg.in/yaml.v3 error to a ParseError located at the line it names.
+func yamlParseError(err error, file string) error {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+	position := Position{File: file}
+	if m := yamlLineRegex.FindStringSubmatchIndex(message); m != nil {
+		position.Line, _ = strconv.Atoi(message[m[2]:m[3]])
+		message = message[:m[0]] + message[m[1]:]
+	}
+	return &ParseError{Position: position, Err: errors.New(strings.TrimSpace(message))}
+}
+
+// unmodelledFields lists the fields the specification defines for a type that the model does
+// not hold, which a strict load accepts although it drops them.
+var unmodelledFields = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(Schema{}): {"$dynamicRef": true, "$dynamicAnchor": true, "$vocabulary": true},
+}
+
+// unknownFields reports every key of the mapping node that no field of t, or of the types it
+// contains, declares. A Reference Object may also have a summary and a description.
+func unknownFields(node *yaml.Node, t reflect.Type, pointer, file string, errs *[]error) {
+	if node.Kind == yaml.AliasNode {
+		// The anchored node is checked where it is defined.
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		if t == reflect.TypeOf(Callback{}) {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if key := node.Content[i].Value; !isExtension(key) && key != "$ref" {
+					unknownFields(node.Content[i+1], reflect.TypeOf(PathItem{}), pointer+"/"+pointerEscape(key), file, errs)
+				}
+			}
+			return
+		}
+		fields := yamlFields(t)
+		_, referable := fields["$ref"]
+		reference := referable && yamlHasKey(node, "$ref")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if isExtension(key.Value) || key.Tag == "!!merge" || unmodelledFields[t][key.Value] ||
+				(reference && (key.Value == "summary" || key.Value == "description")) {
+				continue
+			}
+			keyPointer := pointer + "/" + pointerEscape(key.Value)
+			field, ok := fields[key.Value]
+			if !ok {
+				*errs = append(*errs, &ParseError{
+					Position: Position{File: file, Line: key.Line, Column: key.Column},
+					Err:      fmt.Errorf("%s: unknown field '%s'", keyPointer, key.Value),
+				})
+				continue
+			}
+			unknownFields(node.Content[i+1], field, keyPointer, file, errs)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			unknownFields(node.Content[i+1], t.Elem(), pointer+"/"+pointerEscape(node.Content[i].Value), file, errs)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			unknownFields(item, t.Elem(), pointer+"/"+strconv.Itoa(i), file, errs)
+		}
+	}
+}
+
+// yamlFields returns the types of the fields of a struct keyed by their YAML names.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("yaml")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if strings.Contains(flags, "inline") {
+			for k, v := range yamlFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
@@ -0,0 +1,118 @@
+package oas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string // A substring of the expected error, or empty when the document loads.
+	}{
+		{
+			name: "valid document",
+			yaml: `
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - {$ref: '#/components/parameters/Limit', description: How many pets to list.}
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Ids: {style: simple, explode: false, schema: {type: array, items: {type: string}}}
+            X-Meta: {content: {application/json: {schema: {type: object}}}}
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  parameters:
+    Limit: {name: limit, in: query, schema: {type: integer}}
+  schemas:
+    Pet:
+      type: object
+      $dynamicAnchor: pet
+      discriminator: {propertyName: kind, mapping: {dog: Dog}}
+    Dog: {allOf: [{$ref: '#/components/schemas/Pet'}]}
+`,
+		},
+		{
+			name: "misspelled field",
+			yaml: `
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Pet: {type: object, requried: [name]}
+`,
+			err: "/components/schemas/Pet/requried: unknown field 'requried'",
+		},
+		{
+			name: "misspelled discriminator field",
+			yaml: `
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  schemas:
+    Pet: {type: object, discriminator: {propertyNmae: kind}}
+`,
+			err: "unknown field 'propertyNmae'",
+		},
+		{
+			name: "summary without a reference",
+			yaml: `
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  responses:
+    Empty: {summary: s, description: none}
+`,
+			err: "/components/responses/Empty/summary: unknown field 'summary'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadYAML([]byte(test.yaml), Strict())
+			if test.err == "" && err != nil {
+				t.Errorf("LoadYAML() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("LoadYAML() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestHeaderFields(t *testing.T) {
+	doc, err := LoadYAML([]byte(`
+openapi: 3.1.0
+info: {title: t, version: "1"}
+paths: {}
+components:
+  headers:
+    X-Ids: {style: simple, explode: false, schema: {type: array}}
+    X-Meta: {content: {application/json: {schema: {type: object}}}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, meta := doc.Components.Headers["X-Ids"], doc.Components.Headers["X-Meta"]
+	if ids.Style != "simple" || ids.Explode || !ids.explodeSet {
+		t.Errorf("X-Ids = style %q, explode %t, want simple and an explicit false", ids.Style, ids.Explode)
+	}
+	if meta.Content["application/json"] == nil {
+		t.Error("X-Meta lost its content")
+	}
+	data, err := ids.MarshalJSON()
+	if err != nil || !strings.Contains(string(data), `"explode":false`) {
+		t.Errorf("MarshalJSON() = %s, %v, want explode false", data, err)
+	}
+}
@@ -0,0 +1,162 @@
+package oas
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a parsed media type or media range, such as "text/*;q=0.5".
+type mediaRange struct {
+	typ, subtype string
+	params       map[string]string // The parameters other than q, with lower-case names.
+	q            float64
+}
+
+// parseMediaRange parses a media type with optional wildcards and parameters.
+func parseMediaRange(s string) (mediaRange, bool) {
+	s = strings.TrimSpace(s)
+	if s == "*" {
+		s = "*/*"
+	}
+	mediaType, params, err := mime.ParseMediaType(s)
+	if err != nil {
+		return mediaRange{}, false
+	}
+	typ, subtype, ok := strings.Cut(mediaType, "/")
+	if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+		return mediaRange{}, false
+	}
+	r := mediaRange{typ: typ, subtype: subtype, params: params, q: 1}
+	if q, ok := params["q"]; ok {
+		delete(params, "q")
+		f, err := strconv.ParseFloat(q, 64)
+		if err != nil || f < 0 || f > 1 {
+			return mediaRange{}, false
+		}
+		r.q = f
+	}
+	return r, true
+}
+
+// specificity ranks a media range: a wildcard type is 0, a wildcard subtype 1, a full type
+// 2, and each parameter adds one.
+func (r mediaRange) specificity() int {
+	switch {
+	case r.typ == "*":
+		return 0
+	case r.subtype == "*":
+		return 1
+	}
+	return 2 + len(r.params)
+}
+
+// matches reports whether the media range r includes the media type t: their types match
+// or r has wildcards, and every parameter of r is present in t with the same value.
+func (r mediaRange) matches(t mediaRange) bool {
+	if r.typ != "*" && (r.typ != t.typ || (r.subtype != "*" && r.subtype != t.subtype)) {
+		return false
+	}
+	for name, value := range r.params {
+		if !strings.EqualFold(t.params[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isJSON reports whether a media type carries JSON, such as application/json or
+// application/problem+json.
+func (r mediaRange) isJSON() bool {
+	return (r.typ == "application" && r.subtype == "json") || strings.HasSuffix(r.subtype, "+json")
+}
+
+// isXML reports whether a media type carries XML, such as application/xml or
+// application/atom+xml.
+func (r mediaRange) isXML() bool {
+	return ((r.typ == "application" || r.typ == "text") && r.subtype == "xml") || strings.HasSuffix(r.subtype, "+xml")
+}
+
+// MatchContentType returns the entry of content describing a body of the given Content-Type.
+// Entries are media types or ranges, such as "application/json", "application/*" or "*/*",
+// and may carry parameters. The most specific matching entry is chosen, so a charset
+// parameter of contentType does not prevent "application/json" from matching.
+func MatchContentType(content map[string]*MediaType, contentType string) (string, *MediaType, bool) {
+	t, ok := parseMediaRange(contentType)
+	if !ok || t.typ == "*" || t.subtype == "*" {
+		return "", nil, false
+	}
+	best, bestSpecificity := "", -1
+	for _, key := range sortedKeys(content) {
+		r, ok := parseMediaRange(key)
+		if !ok || !r.matches(t) {
+			continue
+		}
+		if s := r.specificity(); s > bestSpecificity {
+			best, bestSpecificity = key, s
+		}
+	}
+	if bestSpecificity < 0 {
+		return "", nil, false
+	}
+	return best, content[best], true
+}
+
+// NegotiateContent returns the entry of content that best satisfies an Accept header,
+// following RFC 9110: each entry gets the quality of the most specific range of accept that
+// matches it, and the entry with the highest non-zero quality wins. Ties go to the more
+// specific entry, then to the entry accept lists first. An empty accept accepts anything.
+func NegotiateContent(content map[string]*MediaType, accept string) (string, *MediaType, bool) {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		if r, ok := parseMediaRange(part); ok {
+			ranges = append(ranges, r)
+		}
+	}
+
+	type candidate struct {
+		key         string
+		q           float64
+		specificity int
+		order       int
+	}
+	var candidates []candidate
+	for _, key := range sortedKeys(content) {
+		t, ok := parseMediaRange(key)
+		if !ok {
+			continue
+		}
+		c := candidate{key: key, specificity: t.specificity(), order: len(ranges)}
+		rangeSpecificity := -1
+		for i, r := range ranges {
+			// A wildcard entry, such as "application/*", satisfies a range it overlaps.
+			if !r.matches(t) && !t.matches(r) {
+				continue
+			}
+			if s := r.specificity(); s > rangeSpecificity {
+				rangeSpecificity, c.q, c.order = s, r.q, i
+			}
+		}
+		if rangeSpecificity >= 0 && c.q > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.q != b.q {
+			return a.q > b.q
+		}
+		if a.specificity != b.specificity {
+			return a.specificity > b.specificity
+		}
+		return a.order < b.order
+	})
+	return candidates[0].key, content[candidates[0].key], true
+}
@@ -0,0 +1,206 @@
+package oas
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// NormalizeOption configures Validator.Normalize.
+type NormalizeOption func(*normalization)
+
+type normalization struct {
+	direction    Direction
+	stripUnknown bool
+}
+
+// StripUnknown removes the properties of objects that their schema, or one of its allOf
+// schemas, does not declare through properties or patternProperties, whether additional
+// properties are allowed or not. Objects whose additionalProperties is a schema, such as
+// maps, and objects whose schema declares no property at all keep their properties.
+func StripUnknown() NormalizeOption {
+	return func(n *normalization) {
+		n.stripUnknown = true
+	}
+}
+
+// Normalize returns a copy of value transformed to match the schema, and validates it as
+// Validate does. Properties missing from an object are set to the default of their schema,
+// including properties declared by allOf schemas and the components they reference,
+// and strings, such as query parameter values, are converted to the integer, number or
+// boolean the schema expects. Maps and slices are copied; value is not modified. The
+// normalized value is returned even when it does not validate.
+func (v *Validator) Normalize(value interface{}, strict bool, stopOnFailure bool, opts ...NormalizeOption) (interface{}, error) {
+	n := &normalization{direction: v.direction}
+	for _, opt := range opts {
+		opt(n)
+	}
+	normalized := n.normalize(v.root, value)
+	return normalized, v.Validate(normalized, strict, stopOnFailure)
+}
+
+func (n *normalization) normalize(s *compiledSchema, i interface{}) interface{} {
+	if s == nil || s.schema.Bool != nil {
+		return i
+	}
+	i = goValue(i, s.schema.Format)
+	if i == nil {
+		return nil
+	}
+	if str, ok := i.(string); ok {
+		return coerce(s.schema.Type, str)
+	}
+
+	value := reflect.ValueOf(i)
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return i
+		}
+		return n.normalizeObject(s, value)
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, value.Len())
+		for j := range items {
+			item := s.items
+			if j < len(s.prefixItems) {
+				item = s.prefixItems[j]
+			}
+			items[j] = n.normalize(item, value.Index(j).Interface())
+		}
+		return items
+	}
+	return i
+}
+
+func (n *normalization) normalizeObject(s *compiledSchema, value reflect.Value) map[string]interface{} {
+	properties := s.allProperties()
+	strip := n.stripUnknown && s.stripsUnknown(len(properties) > 0)
+	object := make(map[string]interface{}, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		name := iter.Key().String()
+		if property, ok := properties[name]; ok {
+			object[name] = n.normalize(property, iter.Value().Interface())
+		} else if pattern := s.allPatternSchema(name); pattern != nil {
+			object[name] = n.normalize(pattern, iter.Value().Interface())
+		} else if strip {
+			continue
+		} else {
+			object[name] = n.normalize(s.additional, iter.Value().Interface())
+		}
+	}
+	for _, name := range sortedKeys(properties) {
+		property := properties[name]
+		if _, ok := object[name]; ok || property == nil || property.schema.Default == nil {
+			continue
+		}
+		if s.excludes(n.direction, name) {
+			continue
+		}
+		object[name] = clone(property.schema.Default)
+	}
+	return object
+}
+
+// allProperties returns the properties of s, including those of its allOf schemas, which
+// its own properties take precedence over.
+func (s *compiledSchema) allProperties() map[string]*compiledSchema {
+	properties := map[string]*compiledSchema{}
+	var collect func(s *compiledSchema, depth int)
+	collect = func(s *compiledSchema, depth int) {
+		if s == nil || depth > 32 {
+			return
+		}
+		for _, sub := range s.allOf {
+			collect(sub, depth+1)
+		}
+		for name, property := range s.properties {
+			properties[name] = property
+		}
+	}
+	collect(s, 0)
+	return properties
+}
+
+// stripsUnknown reports whether StripUnknown removes the properties s and its allOf schemas
+// do not declare: when they declare some, through properties, as hasProperties tells, or
+// patternProperties, and none of them takes additional properties as a schema.
+func (s *compiledSchema) stripsUnknown(hasProperties bool) bool {
+	declared := hasProperties
+	var open func(s *compiledSchema, depth int) bool
+	open = func(s *compiledSchema, depth int) bool {
+		if s == nil || depth > 32 {
+			return false
+		}
+		if s.additional != nil && s.additional.schema.Bool == nil {
+			return true
+		}
+		declared = declared || len(s.patterns) > 0
+		for _, sub := range s.allOf {
+			if open(sub, depth+1) {
+				return true
+			}
+		}
+		return false
+	}
+	return !open(s, 0) && declared
+}
+
+// allPatternSchema returns the schema of the first patternProperties entry of s, or of its
+// allOf schemas, matching name, or nil.
+func (s *compiledSchema) allPatternSchema(name string) *compiledSchema {
+	var find func(s *compiledSchema, depth int) *compiledSchema
+	find = func(s *compiledSchema, depth int) *compiledSchema {
+		if s == nil || depth > 32 {
+			return nil
+		}
+		if pattern := s.patternSchema(name); pattern != nil {
+			return pattern
+		}
+		for _, sub := range s.allOf {
+			if pattern := find(sub, depth+1); pattern != nil {
+				return pattern
+			}
+		}
+		return nil
+	}
+	return find(s, 0)
+}
+
+// coerce converts str to the first of types it can be parsed as, when the types do not
+// include string. Only "true" and "false" are booleans.
+func coerce(types Types, str string) interface{} {
+	if len(types) == 0 || types.Includes("string") {
+		return str
+	}
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return n
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(str, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if str == "true" || str == "false" {
+				return str == "true"
+			}
+		case "null":
+			if str == "" || str == "null" {
+				return nil
+			}
+		}
+	}
+	return str
+}
+
+// patternSchema returns the schema of the first patternProperties entry matching name, or nil.
+func (s *compiledSchema) patternSchema(name string) *compiledSchema {
+	for _, p := range s.patterns {
+		if p.regexp.MatchString(name) {
+			return p.schema
+		}
+	}
+	return nil
+}
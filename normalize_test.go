@@ -0,0 +1,73 @@
+package oas
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	schemas := map[string]string{
+		"query": `{"type": "object", "properties": {
+			"limit": {"type": "integer", "default": 20},
+			"ratio": {"type": "number"},
+			"active": {"type": "boolean"},
+			"id": {"type": "string", "readOnly": true, "default": "none"}
+		}}`,
+		"closed":   `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": false}`,
+		"open":     `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+		"patterns": `{"type": "object", "patternProperties": {"^x-": {"type": "integer"}}}`,
+		"map":      `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": {"type": "integer"}}`,
+		"free":     `{"type": "object"}`,
+		"allOf": `{"allOf": [
+			{"type": "object", "properties": {"name": {"type": "string"}, "kind": {"type": "string", "default": "pet"}}},
+			{"type": "object", "properties": {"age": {"type": "integer"}}, "patternProperties": {"^x-": {"type": "integer"}}}
+		]}`,
+	}
+	tests := []struct {
+		name   string
+		schema string
+		opts   []NormalizeOption
+		value  map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "defaults and coercion",
+			schema: "query",
+			value:  map[string]interface{}{"ratio": "0.5", "active": "true"},
+			want:   map[string]interface{}{"limit": float64(20), "ratio": 0.5, "active": true},
+		},
+		{name: "false", schema: "query", value: map[string]interface{}{"active": "false", "limit": "5"}, want: map[string]interface{}{"active": false, "limit": int64(5)}},
+		{name: "booleans spelled otherwise", schema: "query", value: map[string]interface{}{"active": "1"}, want: map[string]interface{}{"active": "1", "limit": float64(20)}},
+		{name: "booleans in capitals", schema: "query", value: map[string]interface{}{"active": "TRUE"}, want: map[string]interface{}{"active": "TRUE", "limit": float64(20)}},
+		{name: "unknown property kept", schema: "open", value: map[string]interface{}{"name": "a", "b": 1}, want: map[string]interface{}{"name": "a", "b": 1}},
+		{name: "unknown property stripped", schema: "closed", opts: []NormalizeOption{StripUnknown()}, value: map[string]interface{}{"name": "a", "b": 1}, want: map[string]interface{}{"name": "a"}},
+		{name: "unknown property of an open object stripped", schema: "open", opts: []NormalizeOption{StripUnknown()}, value: map[string]interface{}{"name": "a", "b": 1}, want: map[string]interface{}{"name": "a"}},
+		{name: "pattern property kept", schema: "patterns", opts: []NormalizeOption{StripUnknown()}, value: map[string]interface{}{"x-a": "1", "b": 1}, want: map[string]interface{}{"x-a": int64(1)}},
+		{name: "map entries kept", schema: "map", opts: []NormalizeOption{StripUnknown()}, value: map[string]interface{}{"name": "a", "b": "1"}, want: map[string]interface{}{"name": "a", "b": int64(1)}},
+		{name: "free-form object kept", schema: "free", opts: []NormalizeOption{StripUnknown()}, value: map[string]interface{}{"b": 1}, want: map[string]interface{}{"b": 1}},
+		{
+			name:   "allOf properties",
+			schema: "allOf",
+			opts:   []NormalizeOption{StripUnknown()},
+			value:  map[string]interface{}{"name": "a", "age": "3", "x-b": "2", "c": 1},
+			want:   map[string]interface{}{"name": "a", "kind": "pet", "age": int64(3), "x-b": int64(2)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(schemas[test.schema]), &s); err != nil {
+				t.Fatal(err)
+			}
+			v, err := Compile(&s, WithDirection(RequestDirection))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := v.Normalize(test.value, false, false, test.opts...)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Normalize() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,42 +1,84 @@
 package oas
 
 import (
-	"encoding/json"
-
-	"gopkg.in/yaml.v3"
+	"errors"
+	"strings"
 )
 
 // Server represents a server object in OpenAPI
 type Server struct {
 	URL         string                     `json:"url" yaml:"url"`
-	Description string                     `json:"description,omitempty" yaml:"description"`
-	Variables   map[string]*ServerVariable `json:"variables,omitempty" yaml:"variables"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+	Variables   map[string]*ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+	Extensions  Extensions                 `json:"-" yaml:"-"`
 }
 
 // Tag adds metadata to a single tag that is used by the Operation object
 type Tag struct {
-	Name         string                 `json:"name" yaml:"name"`                           // REQUIRED. The name of the tag.
-	Description  string                 `json:"description,omitempty" yaml:"description"`   // A short description for the tag.
-	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs"` // Additional external documentation for this tag.
+	Name         string                 `json:"name" yaml:"name"`                                     // REQUIRED. The name of the tag.
+	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`   // A short description for the tag.
+	ExternalDocs *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"` // Additional external documentation for this tag.
+	Extensions   Extensions             `json:"-" yaml:"-"`                                           // Specification extensions (x- properties) of the object.
 }
 
 // OpenAPI represents the root document object of the OpenAPI specification
 type OpenAPI struct {
-	OpenAPIVersion string                 `json:"openapi" yaml:"openapi"`                     // REQUIRED. The semantic version number of the OpenAPI specification.
-	Info           *Info                  `json:"info" yaml:"info"`                           // REQUIRED. Provides metadata about the API.
-	ExternalDocs   *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs"` // Additional external documentation.
-	Servers        []*Server              `json:"servers,omitempty" yaml:"servers"`           // An array of Server objects, which provide connectivity information to a target server.
-	Tags           []*Tag                 `json:"tags,omitempty" yaml:"tags"`                 // A list of tags used by the specification with additional metadata.
-	Paths          map[string]*Path       `json:"paths" yaml:"paths"`                         // REQUIRED. The available paths and operations for the API.
-	Components     *Components            `json:"components,omitempty" yaml:"components"`     // An element to hold various schemas for the specification.
+	OpenAPIVersion    string                 `json:"openapi" yaml:"openapi"`                                         // REQUIRED. The semantic version number of the OpenAPI specification.
+	Info              *Info                  `json:"info" yaml:"info"`                                               // REQUIRED. Provides metadata about the API.
+	JSONSchemaDialect string                 `json:"jsonSchemaDialect,omitempty" yaml:"jsonSchemaDialect,omitempty"` // The default $schema value for Schema objects (OpenAPI 3.1).
+	ExternalDocs      *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`           // Additional external documentation.
+	Servers           []*Server              `json:"servers,omitempty" yaml:"servers,omitempty"`                     // An array of Server objects, which provide connectivity information to a target server.
+	Tags              []*Tag                 `json:"tags,omitempty" yaml:"tags,omitempty"`                           // A list of tags used by the specification with additional metadata.
+	Paths             map[string]*Path       `json:"paths" yaml:"paths"`                                             // REQUIRED. The available paths and operations for the API.
+	Webhooks          map[string]*PathItem   `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`                   // The incoming webhooks that may be received as part of this API (OpenAPI 3.1).
+	Components        *Components            `json:"components,omitempty" yaml:"components,omitempty"`               // An element to hold various schemas for the specification.
+	Security          []*SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`                   // The security mechanisms that can be used across the API.
+	Extensions        Extensions             `json:"-" yaml:"-"`                                                     // Specification extensions (x- properties) of the object.
+
+	positions  map[string]Position // The source positions of the parsed nodes, keyed by JSON Pointer.
+	validators *validatorCache     // The validators compiled for bodies and part headers, created on first use.
 }
 
+// Supported versions of the OpenAPI specification, as returned by OpenAPI.Version.
+const (
+	Version30 = "3.0"
+	Version31 = "3.1"
+)
+
+// Version returns the major and minor version declared by OpenAPIVersion, e.g. "3.1".
+func (o *OpenAPI) Version() string {
+	parts := strings.SplitN(strings.TrimSpace(o.OpenAPIVersion), ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// IsVersion31 reports whether the document declares OpenAPI 3.1.
+func (o *OpenAPI) IsVersion31() bool {
+	return o.Version() == Version31
+}
+
+// NewOpenAPI parses an OpenAPI document from JSON or YAML. Swagger 2.0 documents are
+// rejected; convert them with NewOpenAPIFromSwagger instead. When the document is neither
+// valid JSON nor valid YAML, both errors are returned in an UnmarshalError.
 func NewOpenAPI(bytes []byte) (*OpenAPI, error) {
-	openAPI := &OpenAPI{}
-	if err := json.Unmarshal(bytes, openAPI); err != nil {
-		if err := yaml.Unmarshal(bytes, openAPI); err != nil {
-			return nil, err
+	// Only documents that look like JSON are parsed as JSON first, so YAML is parsed once.
+	if sniffFormat(bytes) == "json" {
+		openAPI, jsonErr := LoadJSON(bytes)
+		if jsonErr == nil || errors.Is(jsonErr, errSwagger) {
+			return openAPI, jsonErr
 		}
+		openAPI, yamlErr := LoadYAML(bytes)
+		if yamlErr == nil || errors.Is(yamlErr, errSwagger) {
+			return openAPI, yamlErr
+		}
+		return nil, &UnmarshalError{JSONErr: jsonErr, YAMLErr: yamlErr}
+	}
+	openAPI, yamlErr := LoadYAML(bytes)
+	if yamlErr == nil || errors.Is(yamlErr, errSwagger) {
+		return openAPI, yamlErr
 	}
-	return openAPI, nil
+	_, jsonErr := LoadJSON(bytes)
+	return nil, &UnmarshalError{JSONErr: jsonErr, YAMLErr: yamlErr}
 }
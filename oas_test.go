@@ -0,0 +1,113 @@
+package oas
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		openapi   string
+		version   string
+		version31 bool
+	}{
+		{openapi: "3.0.3", version: Version30},
+		{openapi: "3.1.0", version: Version31, version31: true},
+		{openapi: " 3.1.1 ", version: Version31, version31: true},
+		{openapi: "3.1", version: Version31, version31: true},
+		{openapi: "3", version: ""},
+		{openapi: "", version: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.openapi, func(t *testing.T) {
+			doc := &OpenAPI{OpenAPIVersion: test.openapi}
+			if got := doc.Version(); got != test.version {
+				t.Errorf("Version() = %q, want %q", got, test.version)
+			}
+			if got := doc.IsVersion31(); got != test.version31 {
+				t.Errorf("IsVersion31() = %v, want %v", got, test.version31)
+			}
+		})
+	}
+}
+
+func TestOpenAPI31(t *testing.T) {
+	data, err := os.ReadFile("testdata/openapi31.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := NewOpenAPI(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet := doc.Components.Schemas["Pet"]
+	switch {
+	case doc.JSONSchemaDialect != "https://json-schema.org/draft/2020-12/schema":
+		t.Errorf("jsonSchemaDialect = %q", doc.JSONSchemaDialect)
+	case doc.Info.License.Identifier != "MIT":
+		t.Errorf("license identifier = %q", doc.Info.License.Identifier)
+	case doc.Webhooks["newPet"] == nil || doc.Webhooks["newPet"].Post == nil:
+		t.Error("webhook newPet was not loaded")
+	case !pet.Properties["nickname"].IsNullable():
+		t.Error("nickname is not nullable")
+	case pet.Properties["photo"].ContentEncoding != "base64":
+		t.Errorf("contentEncoding = %q", pet.Properties["photo"].ContentEncoding)
+	case pet.Defs["Tag"] == nil || pet.UnevaluatedProperties == nil || pet.If == nil || pet.DependentSchemas["license"] == nil:
+		t.Error("$defs, unevaluatedProperties, if or dependentSchemas was not loaded")
+	}
+
+	// Re-emitting the document, as JSON or YAML, loses nothing.
+	want, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlData, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for format, data := range map[string][]byte{"JSON": want, "YAML": yamlData} {
+		reloaded, err := NewOpenAPI(data)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if got, _ := json.Marshal(reloaded); string(got) != string(want) {
+			t.Errorf("%s round trip =\n%s\nwant\n%s", format, got, want)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		err   string // A substring of the expected error, or empty when the value is valid.
+	}{
+		{name: "valid", value: `{"name": "Rex", "nickname": null, "kind": "pet", "age": 3, "position": [1, 2], "tag": "good"}`},
+		{name: "const", value: `{"name": "Rex", "kind": "cat"}`, err: "constant"},
+		{name: "type list", value: `{"name": "Rex", "nickname": 1}`, err: "nickname"},
+		{name: "exclusive minimum", value: `{"name": "Rex", "age": 0}`, err: "age"},
+		{name: "exclusive maximum", value: `{"name": "Rex", "kind": "dog", "age": 40}`, err: "age"},
+		{name: "prefixItems", value: `{"name": "Rex", "position": [1, "2"]}`, err: "position/1"},
+		{name: "items after prefixItems", value: `{"name": "Rex", "position": [1, 2, 3]}`, err: "position"},
+		{name: "if then", value: `{"name": "Rex", "kind": "pet", "age": 35}`, err: "age"},
+		{name: "dependentSchemas", value: `{"name": "Rex", "license": "A1"}`, err: "licenseUrl"},
+		{name: "$defs", value: `{"name": "Rex", "tag": ""}`, err: "tag"},
+	}
+	v, err := Compile(pet, WithComponents(doc.Components))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := v.ValidateJSON([]byte(test.value), false, false)
+			if test.err == "" && err != nil {
+				t.Errorf("ValidateJSON() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("ValidateJSON() = %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
@@ -2,18 +2,31 @@ package oas
 
 // Parameter represents a parameter object in OpenAPI
 type Parameter struct {
-	Name            string                `json:"name" yaml:"name"`                                 // REQUIRED. The name of the parameter.
-	In              string                `json:"in" yaml:"in"`                                     // REQUIRED. The location of the parameter.
-	Description     string                `json:"description,omitempty" yaml:"description"`         // A brief description of the parameter.
-	Required        bool                  `json:"required,omitempty" yaml:"required"`               // Determines whether this parameter is mandatory.
-	Deprecated      bool                  `json:"deprecated,omitempty" yaml:"deprecated"`           // Specifies that a parameter is deprecated and should be transitioned out of usage.
-	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue"` // Sets the ability to pass empty-valued parameters.
-	Style           string                `json:"style,omitempty" yaml:"style"`                     // Describes how the parameter value will be serialized.
-	Explode         bool                  `json:"explode,omitempty" yaml:"explode"`                 // When this is true, parameter values of type array or object generate separate parameters for each value of the array or key-value pair of the map.
-	AllowReserved   bool                  `json:"allowReserved,omitempty" yaml:"allowReserved"`     // Determines whether the parameter value should allow reserved characters.
-	Schema          *Schema               `json:"schema,omitempty" yaml:"schema"`                   // The schema defining the type used for the parameter.
-	Example         interface{}           `json:"example,omitempty" yaml:"example"`                 // Example of the parameter's potential value.
-	Examples        map[string]*Example   `json:"examples,omitempty" yaml:"examples"`               // Examples of the parameter's potential value.
-	Content         map[string]*MediaType `json:"content,omitempty" yaml:"content"`                 // A map containing the representations for the parameter.
+	Name            string                `json:"name" yaml:"name"`                                           // REQUIRED. The name of the parameter.
+	In              string                `json:"in" yaml:"in"`                                               // REQUIRED. The location of the parameter.
+	Description     string                `json:"description,omitempty" yaml:"description,omitempty"`         // A brief description of the parameter.
+	Required        bool                  `json:"required,omitempty" yaml:"required,omitempty"`               // Determines whether this parameter is mandatory.
+	Deprecated      bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`           // Specifies that a parameter is deprecated and should be transitioned out of usage.
+	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"` // Sets the ability to pass empty-valued parameters.
+	Style           string                `json:"style,omitempty" yaml:"style,omitempty"`                     // Describes how the parameter value will be serialized.
+	Explode         bool                  `json:"explode,omitempty" yaml:"explode,omitempty"`                 // When this is true, parameter values of type array or object generate separate parameters for each value of the array or key-value pair of the map.
+	AllowReserved   bool                  `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`     // Determines whether the parameter value should allow reserved characters.
+	Schema          *Schema               `json:"schema,omitempty" yaml:"schema,omitempty"`                   // The schema defining the type used for the parameter.
+	Example         interface{}           `json:"example,omitempty" yaml:"example,omitempty"`                 // Example of the parameter's potential value.
+	Examples        map[string]*Example   `json:"examples,omitempty" yaml:"examples,omitempty"`               // Examples of the parameter's potential value.
+	Content         map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`                 // A map containing the representations for the parameter.
 	Ref             string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Extensions      Extensions            `json:"-" yaml:"-"` // Specification extensions (x- properties) of the object.
+
+	explodeSet bool // Whether the document sets explode, so that a false Explode is not the default.
+}
+
+// IsExploded returns the explode setting in effect: Explode when the document sets it,
+// otherwise the default of the style, which is true for form. An Explode set to false in
+// code is only told apart from the default for parameters that were decoded.
+func (p *Parameter) IsExploded() bool {
+	if p.Explode || p.explodeSet {
+		return p.Explode
+	}
+	return p.Style == "form" || (p.Style == "" && (p.In == "query" || p.In == "cookie"))
 }
@@ -2,17 +2,47 @@ package oas
 
 // Path represents a path object in OpenAPI
 type Path struct {
-	Ref         string       `json:"$ref,omitempty" yaml:"$ref"`               // Allows for an external definition of this path item.
-	Summary     string       `json:"summary,omitempty" yaml:"summary"`         // An optional, string summary, intended to apply to all operations in this path.
-	Description string       `json:"description,omitempty" yaml:"description"` // An optional, string description, intended to apply to all operations in this path.
-	Get         *Operation   `json:"get,omitempty" yaml:"get"`                 // A definition of a GET operation on this path.
-	Put         *Operation   `json:"put,omitempty" yaml:"put"`                 // A definition of a PUT operation on this path.
-	Post        *Operation   `json:"post,omitempty" yaml:"post"`               // A definition of a POST operation on this path.
-	Delete      *Operation   `json:"delete,omitempty" yaml:"delete"`           // A definition of a DELETE operation on this path.
-	Options     *Operation   `json:"options,omitempty" yaml:"options"`         // A definition of an OPTIONS operation on this path.
-	Head        *Operation   `json:"head,omitempty" yaml:"head"`               // A definition of a HEAD operation on this path.
-	Patch       *Operation   `json:"patch,omitempty" yaml:"patch"`             // A definition of a PATCH operation on this path.
-	Trace       *Operation   `json:"trace,omitempty" yaml:"trace"`             // A definition of a TRACE operation on this path.
-	Servers     []*Server    `json:"servers,omitempty" yaml:"servers"`         // An alternative server array to service all operations in this path.
-	Parameters  []*Parameter `json:"parameters,omitempty" yaml:"parameters"`   // A list of parameters that are applicable for all the operations described under this path.
+	Ref         string       `json:"$ref,omitempty" yaml:"$ref,omitempty"`               // Allows for an external definition of this path item.
+	Summary     string       `json:"summary,omitempty" yaml:"summary,omitempty"`         // An optional, string summary, intended to apply to all operations in this path.
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"` // An optional, string description, intended to apply to all operations in this path.
+	Get         *Operation   `json:"get,omitempty" yaml:"get,omitempty"`                 // A definition of a GET operation on this path.
+	Put         *Operation   `json:"put,omitempty" yaml:"put,omitempty"`                 // A definition of a PUT operation on this path.
+	Post        *Operation   `json:"post,omitempty" yaml:"post,omitempty"`               // A definition of a POST operation on this path.
+	Delete      *Operation   `json:"delete,omitempty" yaml:"delete,omitempty"`           // A definition of a DELETE operation on this path.
+	Options     *Operation   `json:"options,omitempty" yaml:"options,omitempty"`         // A definition of an OPTIONS operation on this path.
+	Head        *Operation   `json:"head,omitempty" yaml:"head,omitempty"`               // A definition of a HEAD operation on this path.
+	Patch       *Operation   `json:"patch,omitempty" yaml:"patch,omitempty"`             // A definition of a PATCH operation on this path.
+	Trace       *Operation   `json:"trace,omitempty" yaml:"trace,omitempty"`             // A definition of a TRACE operation on this path.
+	Servers     []*Server    `json:"servers,omitempty" yaml:"servers,omitempty"`         // An alternative server array to service all operations in this path.
+	Parameters  []*Parameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`   // A list of parameters that are applicable for all the operations described under this path.
+	Extensions  Extensions   `json:"-" yaml:"-"`                                         // Specification extensions (x- properties) of the object.
+}
+
+// methods lists the HTTP methods a Path can hold, in the order they are declared.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Operation returns the operation for the given lower-case HTTP method, or nil.
+func (p *Path) Operation(method string) *Operation {
+	if p == nil {
+		return nil
+	}
+	switch method {
+	case "get":
+		return p.Get
+	case "put":
+		return p.Put
+	case "post":
+		return p.Post
+	case "delete":
+		return p.Delete
+	case "options":
+		return p.Options
+	case "head":
+		return p.Head
+	case "patch":
+		return p.Patch
+	case "trace":
+		return p.Trace
+	}
+	return nil
 }
@@ -0,0 +1,97 @@
+package oas
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position is a location in the source of a document.
+type Position struct {
+	File   string // The name of the file, empty when the document was not read from a file.
+	Line   int    // The line, starting at 1. Zero when the position is unknown.
+	Column int    // The column, starting at 1.
+}
+
+// IsValid reports whether the position is known.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return p.File
+	}
+	s := strconv.Itoa(p.Line)
+	if p.Column > 0 {
+		s += ":" + strconv.Itoa(p.Column)
+	}
+	if p.File != "" {
+		s = p.File + ":" + s
+	}
+	return s
+}
+
+// Position returns the source position of the node at the JSON Pointer pointer. Entries of
+// objects are located at their key. When the pointer was not recorded, for example because
+// it points into a value, the position of its nearest recorded ancestor is returned. A
+// leading "#", as in a $ref, is ignored.
+func (o *OpenAPI) Position(pointer string) (Position, bool) {
+	if o.positions == nil {
+		return Position{}, false
+	}
+	pointer = strings.TrimPrefix(pointer, "#")
+	for {
+		if p, ok := o.positions[pointer]; ok {
+			return p, true
+		}
+		if pointer == "" {
+			return Position{}, false
+		}
+		i := strings.LastIndex(pointer, "/")
+		if i < 0 {
+			return Position{}, false
+		}
+		pointer = pointer[:i]
+	}
+}
+
+// recordPositions records the position of every node of the parsed document root.
+// Schemas remember their own position for validation errors.
+func (o *OpenAPI) recordPositions(root *yaml.Node, file string) {
+	if len(root.Content) == 0 {
+		return
+	}
+	o.positions = map[string]Position{}
+	var record func(pointer string, key, node *yaml.Node)
+	record = func(pointer string, key, node *yaml.Node) {
+		at := node
+		if key != nil {
+			at = key
+		}
+		o.positions[pointer] = Position{File: file, Line: at.Line, Column: at.Column}
+		// Aliased nodes are located at the alias; their content belongs to the anchor.
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Tag == "!!merge" {
+					continue
+				}
+				record(pointer+"/"+pointerEscape(node.Content[i].Value), node.Content[i], node.Content[i+1])
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				record(pointer+"/"+strconv.Itoa(i), nil, item)
+			}
+		}
+	}
+	record("", nil, root.Content[0])
+
+	Walk(o, Visitor{Enter: func(c *Cursor) WalkAction {
+		if s, ok := c.Value.(*Schema); ok {
+			s.position = o.positions[c.Pointer]
+		}
+		return WalkContinue
+	}})
+}
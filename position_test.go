@@ -0,0 +1,88 @@
+package oas
+
+import (
+	"errors"
+	"testing"
+)
+
+const positionDocument = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1"
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: a pet
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, maxLength: 3}
+`
+
+func TestPosition(t *testing.T) {
+	doc, err := LoadYAML([]byte(positionDocument), WithFileName("pets.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pointer string
+		want    string // The position, or empty when none is known.
+	}{
+		{pointer: "", want: "pets.yaml:1:1"},
+		{pointer: "/info", want: "pets.yaml:2:1"},
+		{pointer: "/paths/~1pets~1{id}/get", want: "pets.yaml:7:5"},
+		{pointer: "/paths/~1pets~1{id}/get/parameters/0", want: "pets.yaml:9:11"},
+		{pointer: "/paths/~1pets~1{id}/get/parameters/0/schema/type", want: "pets.yaml:13:13"},
+		{pointer: "/components/schemas/Pet/required/0", want: "pets.yaml:21:18"},
+		{pointer: "#/components/schemas/Pet", want: "pets.yaml:19:5"},
+		{pointer: "/components/schemas/Pet/properties/name/maxLength", want: "pets.yaml:23:30"},
+		{pointer: "/components/schemas/Pet/example/name", want: "pets.yaml:19:5"},
+	}
+	for _, test := range tests {
+		t.Run(test.pointer, func(t *testing.T) {
+			position, ok := doc.Position(test.pointer)
+			if ok != (test.want != "") || position.String() != test.want {
+				t.Errorf("Position() = %s, %v, want %q", position, ok, test.want)
+			}
+		})
+	}
+
+	if _, ok := (&OpenAPI{}).Position(""); ok {
+		t.Error("Position() of a document built in Go reports a position")
+	}
+
+	err = doc.Components.Schemas["Pet"].Validate(map[string]interface{}{"name": "Rexy"}, false, false)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Position.String() != "pets.yaml:23:9" {
+		t.Errorf("Validate() = %v, want the error located at the name schema", err)
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		position Position
+		want     string
+	}{
+		{position: Position{File: "a.yaml", Line: 3, Column: 7}, want: "a.yaml:3:7"},
+		{position: Position{Line: 3, Column: 7}, want: "3:7"},
+		{position: Position{Line: 3}, want: "3"},
+		{position: Position{File: "a.yaml"}, want: "a.yaml"},
+		{position: Position{}, want: ""},
+	}
+	for _, test := range tests {
+		if got := test.position.String(); got != test.want {
+			t.Errorf("%#v.String() = %q, want %q", test.position, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package oas
+
+import "strings"
+
+// ComponentRef identifies an entry of the Components object.
+type ComponentRef struct {
+	Kind string // The Components field, e.g. "schemas" or "requestBodies".
+	Name string // The name of the entry within that field.
+}
+
+// Pointer returns the JSON Pointer of the component within the document.
+func (r ComponentRef) Pointer() string {
+	return "/components/" + r.Kind + "/" + pointerEscape(r.Name)
+}
+
+// String returns the component as a local $ref value.
+func (r ComponentRef) String() string {
+	return "#" + r.Pointer()
+}
+
+// parseComponentRef parses a local "#/components/<kind>/<name>" reference.
+func parseComponentRef(ref string) (ComponentRef, bool) {
+	rest, ok := strings.CutPrefix(ref, "#/components/")
+	if !ok {
+		return ComponentRef{}, false
+	}
+	kind, name, ok := strings.Cut(rest, "/")
+	if !ok || kind == "" || name == "" || strings.Contains(name, "/") {
+		return ComponentRef{}, false
+	}
+	return ComponentRef{Kind: kind, Name: pointerUnescape(name)}, true
+}
+
+// componentRefs returns every component of c, sorted by kind and name.
+func componentRefs(c *Components) []ComponentRef {
+	if c == nil {
+		return nil
+	}
+	var refs []ComponentRef
+	add := func(kind string, names []string) {
+		for _, name := range names {
+			refs = append(refs, ComponentRef{Kind: kind, Name: name})
+		}
+	}
+	add("schemas", sortedKeys(c.Schemas))
+	add("responses", sortedKeys(c.Responses))
+	add("parameters", sortedKeys(c.Parameters))
+	add("examples", sortedKeys(c.Examples))
+	add("requestBodies", sortedKeys(c.RequestBodies))
+	add("headers", sortedKeys(c.Headers))
+	add("securitySchemes", sortedKeys(c.SecuritySchemes))
+	add("links", sortedKeys(c.Links))
+	add("callbacks", sortedKeys(c.Callbacks))
+	add("pathItems", sortedKeys(c.PathItems))
+	return refs
+}
+
+// ReachableComponents returns every component that is referenced, directly or
+// transitively, from the Paths or Webhooks of the document. The schemas a discriminator
+// mapping names count as referenced.
+func (o *OpenAPI) ReachableComponents() map[ComponentRef]bool {
+	var roots []ComponentRef
+	edges := map[ComponentRef][]ComponentRef{}
+	Walk(o, Visitor{Enter: func(c *Cursor) WalkAction {
+		refs := []string{refOf(c.Value)}
+		if s, ok := c.Value.(*Schema); ok && s.Discriminator != nil {
+			for _, value := range sortedKeys(s.Discriminator.Mapping) {
+				refs = append(refs, s.Discriminator.ref(s.Discriminator.Mapping[value]))
+			}
+		}
+		for _, r := range refs {
+			ref, ok := referencedComponent(r)
+			if !ok {
+				continue
+			}
+			if owner, ok := owningComponent(c.Pointer); ok {
+				edges[owner] = append(edges[owner], ref)
+			} else if strings.HasPrefix(c.Pointer, "/paths/") || strings.HasPrefix(c.Pointer, "/webhooks/") {
+				roots = append(roots, ref)
+			}
+		}
+		return WalkContinue
+	}})
+	reachable := map[ComponentRef]bool{}
+	for len(roots) > 0 {
+		ref := roots[0]
+		roots = roots[1:]
+		if !reachable[ref] {
+			reachable[ref] = true
+			roots = append(roots, edges[ref]...)
+		}
+	}
+	return reachable
+}
+
+// referencedComponent returns the component a local reference points into: the one it
+// names or, for a reference such as "#/components/schemas/Pet/$defs/Tag", the one holding
+// the node it names.
+func referencedComponent(ref string) (ComponentRef, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return ComponentRef{}, false
+	}
+	return owningComponent(pointer)
+}
+
+// owningComponent returns the component that contains the node at pointer.
+func owningComponent(pointer string) (ComponentRef, bool) {
+	rest, ok := strings.CutPrefix(pointer, "/components/")
+	if !ok {
+		return ComponentRef{}, false
+	}
+	kind, rest, _ := strings.Cut(rest, "/")
+	name, _, _ := strings.Cut(rest, "/")
+	if kind == "" || name == "" {
+		return ComponentRef{}, false
+	}
+	return ComponentRef{Kind: kind, Name: pointerUnescape(name)}, true
+}
+
+// UnusedComponents returns the schemas, responses, parameters, examples, request bodies,
+// headers, links, callbacks and path items that are not reachable from the Paths or
+// Webhooks of the document.
+// Security schemes are referenced by name rather than by $ref; see UnusedSecuritySchemes.
+func (o *OpenAPI) UnusedComponents() []ComponentRef {
+	reachable := o.ReachableComponents()
+	var unused []ComponentRef
+	for _, ref := range componentRefs(o.Components) {
+		if ref.Kind != "securitySchemes" && !reachable[ref] {
+			unused = append(unused, ref)
+		}
+	}
+	return unused
+}
+
+// UnusedSecuritySchemes returns the security schemes that no operation requires.
+func (o *OpenAPI) UnusedSecuritySchemes() []ComponentRef {
+	if o.Components == nil {
+		return nil
+	}
+	required := map[string]bool{}
+	Walk(o, Visitor{Enter: func(c *Cursor) WalkAction {
+		if requirement, ok := c.Value.(*SecurityRequirement); ok {
+			for name := range *requirement {
+				required[name] = true
+			}
+		}
+		return WalkContinue
+	}})
+	var unused []ComponentRef
+	for _, name := range sortedKeys(o.Components.SecuritySchemes) {
+		if !required[name] {
+			unused = append(unused, ComponentRef{Kind: "securitySchemes", Name: name})
+		}
+	}
+	return unused
+}
+
+// Prune removes every component reported by UnusedComponents and returns what was removed.
+// Security schemes are left untouched.
+func (o *OpenAPI) Prune() []ComponentRef {
+	unused := o.UnusedComponents()
+	c := o.Components
+	for _, ref := range unused {
+		switch ref.Kind {
+		case "schemas":
+			delete(c.Schemas, ref.Name)
+		case "responses":
+			delete(c.Responses, ref.Name)
+		case "parameters":
+			delete(c.Parameters, ref.Name)
+		case "examples":
+			delete(c.Examples, ref.Name)
+		case "requestBodies":
+			delete(c.RequestBodies, ref.Name)
+		case "headers":
+			delete(c.Headers, ref.Name)
+		case "links":
+			delete(c.Links, ref.Name)
+		case "callbacks":
+			delete(c.Callbacks, ref.Name)
+		case "pathItems":
+			delete(c.PathItems, ref.Name)
+		}
+	}
+	return unused
+}
@@ -0,0 +1,100 @@
+package oas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	tests := []struct {
+		name    string
+		openAPI string
+		removed []string // The removed components, as $ref values.
+	}{
+		{
+			name: "unused schema",
+			openAPI: `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {"responses": {
+				"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}}}},
+				"components": {"schemas": {"Pet": {"type": "object"}, "Owner": {"type": "object"}}}}`,
+			removed: []string{"#/components/schemas/Owner"},
+		},
+		{
+			name: "reference into a component",
+			openAPI: `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "paths": {"/tags": {"get": {"responses": {
+				"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet/$defs/Tag"}}}}}}}},
+				"components": {"schemas": {"Pet": {"type": "object", "$defs": {"Tag": {"type": "string"}}}}}}`,
+		},
+		{
+			name: "transitive references",
+			openAPI: `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {"responses": {
+				"200": {"$ref": "#/components/responses/Pets"}}}}},
+				"components": {
+					"responses": {"Pets": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}},
+					"schemas": {
+						"Pet": {"type": "object", "properties": {"owner": {"$ref": "#/components/schemas/Owner/properties/name"}}},
+						"Owner": {"type": "object", "properties": {"name": {"type": "string"}}},
+						"Orphan": {"$ref": "#/components/schemas/Pet"}
+					}
+				}}`,
+			removed: []string{"#/components/schemas/Orphan"},
+		},
+		{
+			name: "discriminator mapping",
+			openAPI: `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {"responses": {
+				"200": {"description": "ok", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}}}},
+				"components": {"schemas": {
+					"Pet": {"type": "object", "discriminator": {"propertyName": "kind", "mapping": {"dog": "#/components/schemas/Dog", "cat": "Cat"}}},
+					"Dog": {"type": "object"},
+					"Cat": {"type": "object"},
+					"Bird": {"type": "object"}
+				}}}`,
+			removed: []string{"#/components/schemas/Bird"},
+		},
+		{
+			name: "referenced only from webhooks",
+			openAPI: `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "webhooks": {"ping": {"post": {
+				"requestBody": {"$ref": "#/components/requestBodies/Ping"}, "responses": {"200": {"description": "ok"}}}}},
+				"components": {"requestBodies": {"Ping": {"content": {"application/json": {}}}}, "parameters": {"Limit": {"name": "limit", "in": "query"}}}}`,
+			removed: []string{"#/components/parameters/Limit"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := LoadJSON([]byte(test.openAPI))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var removed []string
+			for _, ref := range doc.Prune() {
+				removed = append(removed, ref.String())
+			}
+			if !reflect.DeepEqual(removed, test.removed) {
+				t.Errorf("Prune() = %v, want %v", removed, test.removed)
+			}
+			if unused := doc.UnusedComponents(); len(unused) > 0 {
+				t.Errorf("UnusedComponents() after Prune() = %v, want none", unused)
+			}
+		})
+	}
+}
+
+func TestUnusedSecuritySchemes(t *testing.T) {
+	doc, err := LoadJSON([]byte(`{"openapi": "3.0.3", "info": {"title": "t", "version": "1"},
+		"security": [{"key": []}],
+		"paths": {"/pets": {"get": {"security": [{"oauth": ["read"]}], "responses": {"200": {"description": "ok"}}}}},
+		"components": {"securitySchemes": {
+			"key": {"type": "apiKey", "in": "header", "name": "X-Key"},
+			"oauth": {"type": "oauth2", "flows": {}},
+			"basic": {"type": "http", "scheme": "basic"}
+		}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ComponentRef{{Kind: "securitySchemes", Name: "basic"}}
+	if got := doc.UnusedSecuritySchemes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("UnusedSecuritySchemes() = %v, want %v", got, want)
+	}
+	if removed := doc.Prune(); len(removed) > 0 || len(doc.Components.SecuritySchemes) != 3 {
+		t.Errorf("Prune() = %v and left %d security schemes, want no change", removed, len(doc.Components.SecuritySchemes))
+	}
+}
@@ -2,58 +2,78 @@ package oas
 
 // Response represents a response object in OpenAPI
 type Response struct {
-	Description string                `json:"description" yaml:"description"`   // REQUIRED. A short description of the response.
-	Headers     map[string]*Header    `json:"headers,omitempty" yaml:"headers"` // Maps a header name to its definition.
-	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content"` // A map containing descriptions of potential response payloads.
-	Links       map[string]*Link      `json:"links,omitempty" yaml:"links"`     // A map of operations links that can be followed from the response.
+	Description string                `json:"description" yaml:"description"`             // REQUIRED. A short description of the response.
+	Headers     map[string]*Header    `json:"headers,omitempty" yaml:"headers,omitempty"` // Maps a header name to its definition.
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"` // A map containing descriptions of potential response payloads.
+	Links       map[string]*Link      `json:"links,omitempty" yaml:"links,omitempty"`     // A map of operations links that can be followed from the response.
 	Ref         string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Extensions  Extensions            `json:"-" yaml:"-"` // Specification extensions (x- properties) of the object.
 }
 
 // Header represents a header object in OpenAPI
 type Header struct {
-	Description     string              `json:"description,omitempty" yaml:"description"`
-	Required        bool                `json:"required,omitempty" yaml:"required"`
-	Deprecated      bool                `json:"deprecated,omitempty" yaml:"deprecated"`
-	AllowEmptyValue bool                `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue"`
-	Schema          *Schema             `json:"schema,omitempty" yaml:"schema"`
-	Example         interface{}         `json:"example,omitempty" yaml:"example"`
-	Examples        map[string]*Example `json:"examples,omitempty" yaml:"examples"`
-	Ref             string              `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description     string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Required        bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Deprecated      bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	AllowEmptyValue bool                  `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Style           string                `json:"style,omitempty" yaml:"style,omitempty"`
+	Explode         bool                  `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Schema          *Schema               `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example         interface{}           `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples        map[string]*Example   `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Content         map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+	Ref             string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Extensions      Extensions            `json:"-" yaml:"-"`
+
+	explodeSet bool // Whether the document sets explode, so that a false Explode is not the default.
 }
 
 // MediaType represents a media type object in OpenAPI
 type MediaType struct {
-	Schema   *Schema              `json:"schema,omitempty" yaml:"schema"`
-	Example  interface{}          `json:"example,omitempty" yaml:"example"`
-	Examples map[string]*Example  `json:"examples,omitempty" yaml:"examples"`
-	Encoding map[string]*Encoding `json:"encoding,omitempty" yaml:"encoding"`
+	Schema     *Schema              `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example    interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
+	Examples   map[string]*Example  `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Encoding   map[string]*Encoding `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	Extensions Extensions           `json:"-" yaml:"-"`
 }
 
 // Link represents a link object in OpenAPI
 type Link struct {
-	OperationRef string                 `json:"operationRef,omitempty" yaml:"operationRef"`
-	OperationID  string                 `json:"operationId,omitempty" yaml:"operationId"`
-	Parameters   map[string]interface{} `json:"parameters,omitempty" yaml:"parameters"`
-	RequestBody  interface{}            `json:"requestBody,omitempty" yaml:"requestBody"`
-	Description  string                 `json:"description,omitempty" yaml:"description"`
-	Server       *Server                `json:"server,omitempty" yaml:"server"`
+	OperationRef string                 `json:"operationRef,omitempty" yaml:"operationRef,omitempty"`
+	OperationID  string                 `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody  interface{}            `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Server       *Server                `json:"server,omitempty" yaml:"server,omitempty"`
 	Ref          string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Extensions   Extensions             `json:"-" yaml:"-"`
 }
 
 // Encoding represents an encoding object in OpenAPI
 type Encoding struct {
-	ContentType   string                 `json:"contentType,omitempty" yaml:"contentType"`
-	Headers       map[string]*Header     `json:"headers,omitempty" yaml:"headers"`
-	Style         string                 `json:"style,omitempty" yaml:"style"`
-	Explode       bool                   `json:"explode,omitempty" yaml:"explode"`
-	AllowReserved bool                   `json:"allowReserved,omitempty" yaml:"allowReserved"`
-	Extensions    map[string]interface{} `json:"-" yaml:"-"`
+	ContentType   string             `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Headers       map[string]*Header `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Style         string             `json:"style,omitempty" yaml:"style,omitempty"`
+	Explode       bool               `json:"explode,omitempty" yaml:"explode,omitempty"`
+	AllowReserved bool               `json:"allowReserved,omitempty" yaml:"allowReserved,omitempty"`
+	Extensions    Extensions         `json:"-" yaml:"-"`
+
+	explodeSet bool // Whether the document sets explode, so that a false Explode is not the default.
+}
+
+// IsExploded returns the explode setting in effect: Explode when the document sets it,
+// otherwise the default of the style, which is true for form, the default style.
+func (e *Encoding) IsExploded() bool {
+	if e.Explode || e.explodeSet {
+		return e.Explode
+	}
+	return e.Style == "" || e.Style == "form"
 }
 
 // ServerVariable represents a server variable object in OpenAPI
 type ServerVariable struct {
-	Enum        []string               `json:"enum,omitempty" yaml:"enum"`
-	Default     string                 `json:"default" yaml:"default"`
-	Description string                 `json:"description,omitempty" yaml:"description"`
-	Extensions  map[string]interface{} `json:"-" yaml:"-"`
+	Enum        []string   `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default     string     `json:"default" yaml:"default"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Extensions  Extensions `json:"-" yaml:"-"`
 }
@@ -0,0 +1,438 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RuntimeExpression is a parsed runtime expression, such as "$request.path.id" or
+// "$response.body#/user/id", as used by Link and Callback objects.
+type RuntimeExpression struct {
+	Source   string // "url", "method", "statusCode", "request" or "response".
+	Location string // For the request and response sources: "header", "query", "path" or "body".
+	Name     string // The name of the header, query or path parameter.
+	Pointer  string // The JSON pointer into the body, without the leading "#"; empty for the whole body.
+}
+
+// ParseRuntimeExpression parses a runtime expression. Query and path references are only
+// valid for the request, and header names must be valid tokens.
+func ParseRuntimeExpression(s string) (*RuntimeExpression, error) {
+	switch s {
+	case "$url":
+		return &RuntimeExpression{Source: "url"}, nil
+	case "$method":
+		return &RuntimeExpression{Source: "method"}, nil
+	case "$statusCode":
+		return &RuntimeExpression{Source: "statusCode"}, nil
+	}
+	e := &RuntimeExpression{}
+	var rest string
+	switch {
+	case strings.HasPrefix(s, "$request."):
+		e.Source, rest = "request", strings.TrimPrefix(s, "$request.")
+	case strings.HasPrefix(s, "$response."):
+		e.Source, rest = "response", strings.TrimPrefix(s, "$response.")
+	default:
+		return nil, fmt.Errorf("invalid runtime expression '%s'", s)
+	}
+
+	if rest == "body" || strings.HasPrefix(rest, "body#") {
+		e.Location = "body"
+		if pointer, ok := strings.CutPrefix(rest, "body#"); ok {
+			if pointer != "" && !strings.HasPrefix(pointer, "/") {
+				return nil, fmt.Errorf("runtime expression '%s': invalid JSON pointer '%s'", s, pointer)
+			}
+			e.Pointer = pointer
+		}
+		return e, nil
+	}
+	location, name, ok := strings.Cut(rest, ".")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid runtime expression '%s'", s)
+	}
+	switch location {
+	case "header":
+		if !isToken(name) {
+			return nil, fmt.Errorf("runtime expression '%s': invalid header name '%s'", s, name)
+		}
+	case "query", "path":
+		if e.Source == "response" {
+			return nil, fmt.Errorf("runtime expression '%s': a response has no %s parameters", s, location)
+		}
+	default:
+		return nil, fmt.Errorf("runtime expression '%s': unknown source '%s'", s, location)
+	}
+	e.Location, e.Name = location, name
+	return e, nil
+}
+
+// isToken reports whether s is a token as RFC 9110 defines it.
+func isToken(s string) bool {
+	for _, c := range s {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", c) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// String returns the expression as it is written.
+func (e *RuntimeExpression) String() string {
+	switch e.Source {
+	case "url", "method", "statusCode":
+		return "$" + e.Source
+	}
+	if e.Location == "body" {
+		if e.Pointer != "" {
+			return "$" + e.Source + ".body#" + e.Pointer
+		}
+		return "$" + e.Source + ".body"
+	}
+	return "$" + e.Source + "." + e.Location + "." + e.Name
+}
+
+// RuntimeContext is a captured request and response that runtime expressions are evaluated
+// against. Bodies are captured separately because those of Request and Response can only be
+// read once; when RequestBody or ResponseBody is nil, the body of Request or Response is read
+// and replaced by a copy.
+type RuntimeContext struct {
+	Request        *http.Request
+	RequestBody    []byte
+	PathParameters map[string]string // The values of the path parameters of the request.
+	Response       *http.Response
+	ResponseBody   []byte
+}
+
+// requestBody returns the body of the request, reading it once.
+func (c *RuntimeContext) requestBody() ([]byte, error) {
+	if c.RequestBody == nil && c.Request != nil && c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.RequestBody = body
+	}
+	return c.RequestBody, nil
+}
+
+// responseBody returns the body of the response, reading it once.
+func (c *RuntimeContext) responseBody() ([]byte, error) {
+	if c.ResponseBody == nil && c.Response != nil && c.Response.Body != nil {
+		body, err := io.ReadAll(c.Response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body: %w", err)
+		}
+		c.Response.Body = io.NopCloser(bytes.NewReader(body))
+		c.ResponseBody = body
+	}
+	return c.ResponseBody, nil
+}
+
+// requestURL returns the absolute URL of the request, completing the URL of a server
+// request with its host and scheme.
+func (c *RuntimeContext) requestURL() string {
+	u := *c.Request.URL
+	if u.Host == "" {
+		u.Host = c.Request.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if c.Request.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return u.String()
+}
+
+// Evaluate returns the value of the expression in c. Headers, parameters, the URL and the
+// method are strings and the status code is an int. A body is decoded as JSON when it is
+// JSON, and a JSON pointer selects a value within it; a body that is not JSON is returned as
+// a string.
+func (e *RuntimeExpression) Evaluate(c *RuntimeContext) (interface{}, error) {
+	if e.Source == "request" || e.Source == "url" || e.Source == "method" {
+		if c.Request == nil {
+			return nil, fmt.Errorf("%s: no request", e)
+		}
+	} else if c.Response == nil {
+		return nil, fmt.Errorf("%s: no response", e)
+	}
+
+	switch e.Source {
+	case "url":
+		return c.requestURL(), nil
+	case "method":
+		return c.Request.Method, nil
+	case "statusCode":
+		return c.Response.StatusCode, nil
+	}
+
+	header := c.Request.Header
+	if e.Source == "response" {
+		header = c.Response.Header
+	}
+	switch e.Location {
+	case "header":
+		values := header.Values(e.Name)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%s: header is not present", e)
+		}
+		return strings.Join(values, ", "), nil
+	case "query":
+		values, ok := c.Request.URL.Query()[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s: query parameter is not present", e)
+		}
+		return values[0], nil
+	case "path":
+		value, ok := c.PathParameters[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s: path parameter is not present", e)
+		}
+		return value, nil
+	}
+
+	read := c.requestBody
+	if e.Source == "response" {
+		read = c.responseBody
+	}
+	body, err := read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e, err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		if e.Pointer != "" {
+			return nil, fmt.Errorf("%s: body is not JSON", e)
+		}
+		return string(body), nil
+	}
+	value, err = jsonPointerValue(value, e.Pointer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e, err)
+	}
+	return value, nil
+}
+
+// jsonPointerValue returns the value a JSON pointer designates in a decoded JSON document.
+func jsonPointerValue(value interface{}, pointer string) (interface{}, error) {
+	tokens, err := pointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("property '%s' is not present", token)
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) || (token != "0" && strings.HasPrefix(token, "0")) {
+				return nil, fmt.Errorf("index '%s' is out of range", token)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("cannot select '%s' in a value that is not an object or array", token)
+		}
+	}
+	return value, nil
+}
+
+// EvaluateRuntimeExpressions evaluates a value of a Link or a Callback key. A string that is a
+// runtime expression evaluates to the value of the expression; expressions embedded in a
+// string between braces, such as "{$request.body#/url}/events", are replaced by their value,
+// JSON-encoded unless it is a string or a number. Other values are constants.
+func EvaluateRuntimeExpressions(value interface{}, c *RuntimeContext) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+	if strings.HasPrefix(s, "$") {
+		e, err := ParseRuntimeExpression(s)
+		if err != nil {
+			return nil, err
+		}
+		return e.Evaluate(c)
+	}
+	if !strings.Contains(s, "{") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "{$")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("runtime expression in '%s' is not closed", value)
+		}
+		b.WriteString(s[:start])
+		e, err := ParseRuntimeExpression(s[start+1 : start+end])
+		if err != nil {
+			return nil, err
+		}
+		v, err := e.Evaluate(c)
+		if err != nil {
+			return nil, err
+		}
+		text, err := expressionText(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e, err)
+		}
+		b.WriteString(text)
+		s = s[start+end+1:]
+	}
+}
+
+// expressionText returns the text that replaces an embedded runtime expression.
+func expressionText(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case int:
+		return strconv.Itoa(x), nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// LinkTarget is the operation a Link designates, with the values computed for it.
+type LinkTarget struct {
+	Path        string                 // The path template of the operation.
+	Method      string                 // The lower-case HTTP method of the operation.
+	Operation   *Operation             // The operation itself.
+	Parameters  map[string]interface{} // The parameter values, keyed as in Link.Parameters, e.g. "id" or "path.id".
+	RequestBody interface{}            // The request body, or nil.
+	Server      *Server                // The server of the link, or nil to use those of the operation.
+}
+
+// ResolveLink finds the operation a link designates, by operationId or by a local
+// operationRef such as "#/paths/~1users~1{id}/get", and evaluates its parameters and
+// request body in c.
+func (o *OpenAPI) ResolveLink(link *Link, c *RuntimeContext) (*LinkTarget, error) {
+	if link == nil {
+		return nil, errors.New("link is nil")
+	}
+	if link.Ref != "" {
+		target, err := o.resolveRef(link.Ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved, ok := target.(*Link)
+		if !ok {
+			return nil, fmt.Errorf("reference '%s' is not a link", link.Ref)
+		}
+		link = resolved
+	}
+
+	t := &LinkTarget{Server: link.Server, Parameters: map[string]interface{}{}}
+	var err error
+	switch {
+	case link.OperationID != "" && link.OperationRef != "":
+		return nil, errors.New("link sets both operationId and operationRef")
+	case link.OperationID != "":
+		t.Path, t.Method, t.Operation, err = o.operationByID(link.OperationID)
+	case link.OperationRef != "":
+		t.Path, t.Method, t.Operation, err = o.operationByRef(link.OperationRef)
+	default:
+		return nil, errors.New("link sets neither operationId nor operationRef")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedKeys(link.Parameters) {
+		value, err := EvaluateRuntimeExpressions(link.Parameters[name], c)
+		if err != nil {
+			return nil, fmt.Errorf("link parameter '%s': %w", name, err)
+		}
+		t.Parameters[name] = value
+	}
+	if link.RequestBody != nil {
+		if t.RequestBody, err = EvaluateRuntimeExpressions(link.RequestBody, c); err != nil {
+			return nil, fmt.Errorf("link request body: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// operationByID returns the operation with the given operationId.
+func (o *OpenAPI) operationByID(id string) (string, string, *Operation, error) {
+	for _, path := range sortedKeys(o.Paths) {
+		for _, method := range methods {
+			if op := o.Paths[path].Operation(method); op != nil && op.OperationID == id {
+				return path, method, op, nil
+			}
+		}
+	}
+	return "", "", nil, fmt.Errorf("operation '%s' not found", id)
+}
+
+// operationByRef returns the operation of a local "#/paths/<path>/<method>" reference.
+func (o *OpenAPI) operationByRef(ref string) (string, string, *Operation, error) {
+	rest, ok := strings.CutPrefix(ref, "#/paths/")
+	if !ok {
+		return "", "", nil, fmt.Errorf("operation reference '%s' is not supported; only local references to paths are", ref)
+	}
+	escaped, method, ok := strings.Cut(rest, "/")
+	if !ok {
+		return "", "", nil, fmt.Errorf("operation reference '%s' has no method", ref)
+	}
+	path := pointerUnescape(escaped)
+	if op := o.Paths[path].Operation(method); op != nil {
+		return path, method, op, nil
+	}
+	return "", "", nil, fmt.Errorf("operation reference '%s' not found", ref)
+}
+
+// CallbackTarget is a request a Callback describes, with its URL computed.
+type CallbackTarget struct {
+	Expression string    // The key of the callback, e.g. "{$request.body#/callbackUrl}".
+	URL        string    // The URL the expression evaluates to.
+	PathItem   *PathItem // The operations the callback URL serves.
+}
+
+// CallbackURLs evaluates the expressions of a callback in c, sorted by expression.
+func (o *OpenAPI) CallbackURLs(callback *Callback, c *RuntimeContext) ([]CallbackTarget, error) {
+	if callback == nil {
+		return nil, errors.New("callback is nil")
+	}
+	if callback.Ref != "" {
+		target, err := o.resolveRef(callback.Ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved, ok := target.(*Callback)
+		if !ok {
+			return nil, fmt.Errorf("reference '%s' is not a callback", callback.Ref)
+		}
+		callback = resolved
+	}
+	var targets []CallbackTarget
+	for _, expression := range sortedKeys(callback.Expression) {
+		value, err := EvaluateRuntimeExpressions(expression, c)
+		if err != nil {
+			return nil, fmt.Errorf("callback '%s': %w", expression, err)
+		}
+		u, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("callback '%s': URL is not a string", expression)
+		}
+		targets = append(targets, CallbackTarget{Expression: expression, URL: u, PathItem: callback.Expression[expression]})
+	}
+	return targets, nil
+}
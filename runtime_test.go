@@ -0,0 +1,202 @@
+package oas
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRuntimeExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       RuntimeExpression
+		err        string // A substring of the expected error, or empty when the expression parses.
+	}{
+		{expression: "$url", want: RuntimeExpression{Source: "url"}},
+		{expression: "$method", want: RuntimeExpression{Source: "method"}},
+		{expression: "$statusCode", want: RuntimeExpression{Source: "statusCode"}},
+		{expression: "$request.path.id", want: RuntimeExpression{Source: "request", Location: "path", Name: "id"}},
+		{expression: "$request.query.page.size", want: RuntimeExpression{Source: "request", Location: "query", Name: "page.size"}},
+		{expression: "$request.header.X-Request-ID", want: RuntimeExpression{Source: "request", Location: "header", Name: "X-Request-ID"}},
+		{expression: "$response.header.Location", want: RuntimeExpression{Source: "response", Location: "header", Name: "Location"}},
+		{expression: "$request.body", want: RuntimeExpression{Source: "request", Location: "body"}},
+		{expression: "$response.body#/user/id", want: RuntimeExpression{Source: "response", Location: "body", Pointer: "/user/id"}},
+		{expression: "$response.body#", want: RuntimeExpression{Source: "response", Location: "body"}},
+		{expression: "$response.body#user", err: "invalid JSON pointer"},
+		{expression: "$response.query.id", err: "a response has no query parameters"},
+		{expression: "$request.header.X Id", err: "invalid header name"},
+		{expression: "$request.cookie.id", err: "unknown source 'cookie'"},
+		{expression: "$request.path", err: "invalid runtime expression"},
+		{expression: "$request.path.", err: "invalid runtime expression"},
+		{expression: "$status", err: "invalid runtime expression"},
+		{expression: "request.path.id", err: "invalid runtime expression"},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			e, err := ParseRuntimeExpression(test.expression)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("ParseRuntimeExpression() = %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *e != test.want {
+				t.Errorf("ParseRuntimeExpression() = %+v, want %+v", *e, test.want)
+			}
+			if want := strings.TrimSuffix(test.expression, "#"); e.String() != want {
+				t.Errorf("String() = %q, want %q", e.String(), want)
+			}
+		})
+	}
+}
+
+// runtimeContext returns a captured request and response for evaluating runtime expressions.
+func runtimeContext() *RuntimeContext {
+	request := httptest.NewRequest(http.MethodPost, "/users/42?page=2&page=3", strings.NewReader(`{"callbackUrl": "https://example.com/hooks", "tags": ["a", "b"], "a/b": 1}`))
+	request.Header.Add("X-Trace", "t1")
+	request.Header.Add("X-Trace", "t2")
+	response := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": {"/users/42"}},
+		Body:       io.NopCloser(strings.NewReader(`{"user": {"id": 42, "name": "Ada"}}`)),
+	}
+	return &RuntimeContext{Request: request, PathParameters: map[string]string{"id": "42"}, Response: response}
+}
+
+func TestEvaluateRuntimeExpressions(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+		err   string // A substring of the expected error, or empty when the value evaluates.
+	}{
+		{name: "url", value: "$url", want: "http://example.com/users/42?page=2&page=3"},
+		{name: "method", value: "$method", want: "POST"},
+		{name: "status code", value: "$statusCode", want: 201},
+		{name: "path parameter", value: "$request.path.id", want: "42"},
+		{name: "first query value", value: "$request.query.page", want: "2"},
+		{name: "repeated header", value: "$request.header.x-trace", want: "t1, t2"},
+		{name: "response header", value: "$response.header.Location", want: "/users/42"},
+		{name: "body pointer", value: "$response.body#/user/id", want: 42.0},
+		{name: "array index", value: "$request.body#/tags/1", want: "b"},
+		{name: "escaped pointer", value: "$request.body#/a~1b", want: 1.0},
+		{name: "whole body", value: "$response.body", want: map[string]interface{}{"user": map[string]interface{}{"id": 42.0, "name": "Ada"}}},
+		{name: "embedded expressions", value: "{$request.body#/callbackUrl}/users/{$response.body#/user/id}", want: "https://example.com/hooks/users/42"},
+		{name: "embedded object", value: "user={$response.body#/user}", want: `user={"id":42,"name":"Ada"}`},
+		{name: "constant", value: "users", want: "users"},
+		{name: "non-string constant", value: 7, want: 7},
+		{name: "missing header", value: "$request.header.X-Missing", err: "header is not present"},
+		{name: "missing query parameter", value: "$request.query.size", err: "query parameter is not present"},
+		{name: "missing property", value: "$response.body#/user/email", err: "property 'email' is not present"},
+		{name: "index out of range", value: "$request.body#/tags/2", err: "index '2' is out of range"},
+		{name: "leading zero index", value: "$request.body#/tags/01", err: "index '01' is out of range"},
+		{name: "unclosed expression", value: "{$request.path.id", err: "is not closed"},
+		{name: "invalid embedded expression", value: "{$request.cookie.id}", err: "unknown source"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := EvaluateRuntimeExpressions(test.value, runtimeContext())
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("EvaluateRuntimeExpressions() = %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("EvaluateRuntimeExpressions() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+
+	// The bodies stay readable after they were captured.
+	c := runtimeContext()
+	if _, err := EvaluateRuntimeExpressions("$request.body", c); err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := io.ReadAll(c.Request.Body); !strings.Contains(string(body), "callbackUrl") {
+		t.Errorf("request body after evaluation = %q, want it unchanged", body)
+	}
+}
+
+func TestResolveLink(t *testing.T) {
+	doc, err := LoadJSON([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Users", "version": "1"},
+		"paths": {"/users/{id}": {"get": {"operationId": "getUser", "responses": {"200": {"description": "user"}}}}},
+		"components": {"links": {"User": {"operationId": "getUser", "parameters": {"id": "$response.body#/user/id"}}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		link *Link
+		err  string // A substring of the expected error, or empty when the link resolves.
+	}{
+		{name: "operationId", link: &Link{OperationID: "getUser", Parameters: map[string]interface{}{"id": "$response.body#/user/id"}}},
+		{name: "operationRef", link: &Link{OperationRef: "#/paths/~1users~1{id}/get", Parameters: map[string]interface{}{"id": "$response.body#/user/id"}}},
+		{name: "reference", link: &Link{Ref: "#/components/links/User"}},
+		{name: "unknown operationId", link: &Link{OperationID: "getUsers"}, err: "operation 'getUsers' not found"},
+		{name: "unknown operationRef", link: &Link{OperationRef: "#/paths/~1users/get"}, err: "not found"},
+		{name: "remote operationRef", link: &Link{OperationRef: "https://example.com/api.json#/paths/~1users/get"}, err: "not supported"},
+		{name: "both", link: &Link{OperationID: "getUser", OperationRef: "#/paths/~1users~1{id}/get"}, err: "both"},
+		{name: "neither", link: &Link{}, err: "neither"},
+		{name: "failing parameter", link: &Link{OperationID: "getUser", Parameters: map[string]interface{}{"id": "$response.body#/id"}}, err: "link parameter 'id'"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target, err := doc.ResolveLink(test.link, runtimeContext())
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("ResolveLink() = %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if target.Path != "/users/{id}" || target.Method != "get" || target.Operation != doc.Paths["/users/{id}"].Get {
+				t.Errorf("ResolveLink() = %s %s, want get /users/{id}", target.Method, target.Path)
+			}
+			if target.Parameters["id"] != 42.0 {
+				t.Errorf("parameters = %v, want id 42", target.Parameters)
+			}
+		})
+	}
+}
+
+func TestCallbackURLs(t *testing.T) {
+	callback := &Callback{Expression: map[string]*PathItem{
+		"{$request.body#/callbackUrl}/events": {},
+		"https://audit.example.com/{$method}": {},
+	}}
+	targets, err := (&OpenAPI{}).CallbackURLs(callback, runtimeContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	for _, target := range targets {
+		urls = append(urls, target.URL)
+	}
+	want := []string{"https://audit.example.com/POST", "https://example.com/hooks/events"} // Sorted by expression.
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("CallbackURLs() = %v, want %v", urls, want)
+	}
+	callback.Expression = map[string]*PathItem{"{$request.body#/tags}": {}}
+	if _, err := (&OpenAPI{}).CallbackURLs(callback, runtimeContext()); err != nil {
+		t.Errorf("CallbackURLs() = %v, want a JSON-encoded URL", err)
+	}
+	callback.Expression = map[string]*PathItem{"$statusCode": {}}
+	if _, err := (&OpenAPI{}).CallbackURLs(callback, runtimeContext()); err == nil || !strings.Contains(err.Error(), "not a string") {
+		t.Errorf("CallbackURLs() = %v, want an error for a URL that is not a string", err)
+	}
+}
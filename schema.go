@@ -1,49 +1,226 @@
 package oas
 
+import (
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Schema represents a schema object in OpenAPI
 type Schema struct {
-	Title                string                 `json:"title,omitempty" yaml:"title"`                               // The title of the schema.
-	MultipleOf           *float64               `json:"multipleOf,omitempty" yaml:"multipleOf"`                     // Constrains the value to be a multiple of a given number.
-	Maximum              *float64               `json:"maximum,omitempty" yaml:"maximum"`                           // Constrains the value to be at most a maximum.
-	ExclusiveMaximum     bool                   `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum"`         // Whether `maximum` is exclusive.
-	Minimum              *float64               `json:"minimum,omitempty" yaml:"minimum"`                           // Constrains the value to be at least a minimum.
-	ExclusiveMinimum     bool                   `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum"`         // Whether `minimum` is exclusive.
-	MaxLength            *int                   `json:"maxLength,omitempty" yaml:"maxLength"`                       // Constrains the length of a string.
-	MinLength            *int                   `json:"minLength,omitempty" yaml:"minLength"`                       // Constrains the length of a string.
-	Pattern              *string                `json:"pattern,omitempty" yaml:"pattern"`                           // A regular expression a string value must conform to.
-	MaxItems             *int                   `json:"maxItems,omitempty" yaml:"maxItems"`                         // Constrains the number of items in an array.
-	MinItems             *int                   `json:"minItems,omitempty" yaml:"minItems"`                         // Constrains the number of items in an array.
-	UniqueItems          bool                   `json:"uniqueItems,omitempty" yaml:"uniqueItems"`                   // Ensures that items in an array are unique.
-	MaxProperties        *int                   `json:"maxProperties,omitempty" yaml:"maxProperties"`               // Constrains the number of properties in an object.
-	MinProperties        *int                   `json:"minProperties,omitempty" yaml:"minProperties"`               // Constrains the number of properties in an object.
-	Required             []string               `json:"required,omitempty" yaml:"required"`                         // Lists the required properties.
-	Enum                 []interface{}          `json:"enum,omitempty" yaml:"enum"`                                 // Specifies the allowed values.
-	Type                 string                 `json:"type,omitempty" yaml:"type"`                                 // The type of the schema (e.g., string, integer, etc.).
-	AllOf                []*Schema              `json:"allOf,omitempty" yaml:"allOf"`                               // Combines subschemas into a single schema.
-	OneOf                []*Schema              `json:"oneOf,omitempty" yaml:"oneOf"`                               // Combines subschemas into a single schema, only one of which should validate.
-	AnyOf                []*Schema              `json:"anyOf,omitempty" yaml:"anyOf"`                               // Combines subschemas into a single schema, any of which can validate.
-	Not                  *Schema                `json:"not,omitempty" yaml:"not"`                                   // Inverts a schema, ensuring it does not validate.
-	Items                *Schema                `json:"items,omitempty" yaml:"items"`                               // Defines the type of items in an array schema.
-	Properties           map[string]*Schema     `json:"properties,omitempty" yaml:"properties"`                     // Defines the properties for an object schema.
-	AdditionalProperties *Schema                `json:"additionalProperties,omitempty" yaml:"additionalProperties"` // Allows for additional properties in an object schema.
-	Description          string                 `json:"description,omitempty" yaml:"description"`                   // A description of the schema.
-	Format               string                 `json:"format,omitempty" yaml:"format"`                             // Provides additional data type information.
-	Default              interface{}            `json:"default,omitempty" yaml:"default"`                           // The default value for the schema.
-	Nullable             bool                   `json:"nullable,omitempty" yaml:"nullable"`                         // Allows the schema to be null.
-	ReadOnly             bool                   `json:"readOnly,omitempty" yaml:"readOnly"`                         // Marks the schema as read-only.
-	WriteOnly            bool                   `json:"writeOnly,omitempty" yaml:"writeOnly"`                       // Marks the schema as write-only.
-	XML                  *XML                   `json:"xml,omitempty" yaml:"xml"`                                   // XML modeling information.
-	ExternalDocs         *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs"`                 // Additional external documentation.
-	Example              interface{}            `json:"example,omitempty" yaml:"example"`                           // An example of the schema's potential value.
-	Deprecated           bool                   `json:"deprecated,omitempty" yaml:"deprecated"`                     // Marks the schema as deprecated.
-	Ref                  string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Title                 string                 `json:"title,omitempty" yaml:"title,omitempty"`                                 // The title of the schema.
+	MultipleOf            *float64               `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`                       // Constrains the value to be a multiple of a given number.
+	Maximum               *float64               `json:"maximum,omitempty" yaml:"maximum,omitempty"`                             // Constrains the value to be at most a maximum.
+	ExclusiveMaximum      *ExclusiveBound        `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`           // Whether `maximum` is exclusive (3.0), or an exclusive maximum (3.1).
+	Minimum               *float64               `json:"minimum,omitempty" yaml:"minimum,omitempty"`                             // Constrains the value to be at least a minimum.
+	ExclusiveMinimum      *ExclusiveBound        `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`           // Whether `minimum` is exclusive (3.0), or an exclusive minimum (3.1).
+	MaxLength             *int                   `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`                         // Constrains the length of a string.
+	MinLength             *int                   `json:"minLength,omitempty" yaml:"minLength,omitempty"`                         // Constrains the length of a string.
+	Pattern               *string                `json:"pattern,omitempty" yaml:"pattern,omitempty"`                             // A regular expression a string value must conform to.
+	MaxItems              *int                   `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`                           // Constrains the number of items in an array.
+	MinItems              *int                   `json:"minItems,omitempty" yaml:"minItems,omitempty"`                           // Constrains the number of items in an array.
+	UniqueItems           bool                   `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`                     // Ensures that items in an array are unique.
+	MaxProperties         *int                   `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`                 // Constrains the number of properties in an object.
+	MinProperties         *int                   `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`                 // Constrains the number of properties in an object.
+	Required              []string               `json:"required,omitempty" yaml:"required,omitempty"`                           // Lists the required properties.
+	Enum                  []interface{}          `json:"enum,omitempty" yaml:"enum,omitempty"`                                   // Specifies the allowed values.
+	Type                  Types                  `json:"type,omitempty" yaml:"type,omitempty"`                                   // The type of the schema (e.g., string, integer, etc.), or a list of types (3.1).
+	Const                 interface{}            `json:"const,omitempty" yaml:"const,omitempty"`                                 // The only allowed value (3.1).
+	AllOf                 []*Schema              `json:"allOf,omitempty" yaml:"allOf,omitempty"`                                 // Combines subschemas into a single schema.
+	OneOf                 []*Schema              `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`                                 // Combines subschemas into a single schema, only one of which should validate.
+	AnyOf                 []*Schema              `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`                                 // Combines subschemas into a single schema, any of which can validate.
+	Not                   *Schema                `json:"not,omitempty" yaml:"not,omitempty"`                                     // Inverts a schema, ensuring it does not validate.
+	If                    *Schema                `json:"if,omitempty" yaml:"if,omitempty"`                                       // Selects whether Then or Else applies (3.1).
+	Then                  *Schema                `json:"then,omitempty" yaml:"then,omitempty"`                                   // Applies when the value validates against If (3.1).
+	Else                  *Schema                `json:"else,omitempty" yaml:"else,omitempty"`                                   // Applies when the value does not validate against If (3.1).
+	DependentSchemas      map[string]*Schema     `json:"dependentSchemas,omitempty" yaml:"dependentSchemas,omitempty"`           // Schemas the object must validate against when a property is present (3.1).
+	DependentRequired     map[string][]string    `json:"dependentRequired,omitempty" yaml:"dependentRequired,omitempty"`         // Properties that are required when a property is present (3.1).
+	PrefixItems           []*Schema              `json:"prefixItems,omitempty" yaml:"prefixItems,omitempty"`                     // Defines the leading items of an array schema by position (3.1).
+	Items                 *Schema                `json:"items,omitempty" yaml:"items,omitempty"`                                 // Defines the type of items in an array schema.
+	Contains              *Schema                `json:"contains,omitempty" yaml:"contains,omitempty"`                           // A schema some items of an array must validate against (3.1).
+	MinContains           *int                   `json:"minContains,omitempty" yaml:"minContains,omitempty"`                     // Constrains the number of items matching Contains (3.1).
+	MaxContains           *int                   `json:"maxContains,omitempty" yaml:"maxContains,omitempty"`                     // Constrains the number of items matching Contains (3.1).
+	UnevaluatedItems      *Schema                `json:"unevaluatedItems,omitempty" yaml:"unevaluatedItems,omitempty"`           // Applies to items not evaluated by other keywords (3.1).
+	Properties            map[string]*Schema     `json:"properties,omitempty" yaml:"properties,omitempty"`                       // Defines the properties for an object schema.
+	PatternProperties     map[string]*Schema     `json:"patternProperties,omitempty" yaml:"patternProperties,omitempty"`         // Defines the properties whose names match a regular expression (3.1).
+	AdditionalProperties  *Schema                `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`   // Allows for additional properties in an object schema.
+	PropertyNames         *Schema                `json:"propertyNames,omitempty" yaml:"propertyNames,omitempty"`                 // A schema every property name must validate against (3.1).
+	UnevaluatedProperties *Schema                `json:"unevaluatedProperties,omitempty" yaml:"unevaluatedProperties,omitempty"` // Applies to properties not evaluated by other keywords (3.1).
+	Description           string                 `json:"description,omitempty" yaml:"description,omitempty"`                     // A description of the schema.
+	Format                string                 `json:"format,omitempty" yaml:"format,omitempty"`                               // Provides additional data type information.
+	ContentEncoding       string                 `json:"contentEncoding,omitempty" yaml:"contentEncoding,omitempty"`             // The encoding of string content, e.g. base64 (3.1).
+	ContentMediaType      string                 `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"`           // The media type of string content (3.1).
+	ContentSchema         *Schema                `json:"contentSchema,omitempty" yaml:"contentSchema,omitempty"`                 // The schema of decoded string content (3.1).
+	Default               interface{}            `json:"default,omitempty" yaml:"default,omitempty"`                             // The default value for the schema.
+	Nullable              bool                   `json:"nullable,omitempty" yaml:"nullable,omitempty"`                           // Allows the schema to be null.
+	ReadOnly              bool                   `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`                           // Marks the schema as read-only.
+	WriteOnly             bool                   `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`                         // Marks the schema as write-only.
+	Discriminator         *Discriminator         `json:"discriminator,omitempty" yaml:"discriminator,omitempty"`                 // Selects the schema of a polymorphic value by one of its properties.
+	XML                   *XML                   `json:"xml,omitempty" yaml:"xml,omitempty"`                                     // XML modeling information.
+	ExternalDocs          *ExternalDocumentation `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`                   // Additional external documentation.
+	Example               interface{}            `json:"example,omitempty" yaml:"example,omitempty"`                             // An example of the schema's potential value.
+	Examples              []interface{}          `json:"examples,omitempty" yaml:"examples,omitempty"`                           // Examples of the schema's potential values (3.1).
+	Deprecated            bool                   `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`                       // Marks the schema as deprecated.
+	Ref                   string                 `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	ID                    string                 `json:"$id,omitempty" yaml:"$id,omitempty"`           // The URI identifying the schema (3.1).
+	Dialect               string                 `json:"$schema,omitempty" yaml:"$schema,omitempty"`   // The JSON Schema dialect of the schema (3.1).
+	Anchor                string                 `json:"$anchor,omitempty" yaml:"$anchor,omitempty"`   // A plain-name fragment identifying the schema (3.1).
+	Comment               string                 `json:"$comment,omitempty" yaml:"$comment,omitempty"` // A comment for schema maintainers (3.1).
+	Defs                  map[string]*Schema     `json:"$defs,omitempty" yaml:"$defs,omitempty"`       // Reusable schemas local to the schema (3.1).
+	Bool                  *bool                  `json:"-" yaml:"-"`                                   // Set when the schema is the boolean schema true or false.
+	Extensions            Extensions             `json:"-" yaml:"-"`                                   // Specification extensions (x- properties) of the object.
+
+	position  Position // The source position of the schema, set when the document is parsed.
+	constNull bool     // Whether the source declares const: null, which a nil Const cannot tell apart.
 }
 
 // XML represents XML modeling information for an object in OpenAPI
 type XML struct {
-	Name      string `json:"name,omitempty" yaml:"name"`           // Replaces the name of the element/attribute used for the described schema property.
-	Namespace string `json:"namespace,omitempty" yaml:"namespace"` // The URL of the namespace definition.
-	Prefix    string `json:"prefix,omitempty" yaml:"prefix"`       // The prefix to be used for the name.
-	Attribute bool   `json:"attribute,omitempty" yaml:"attribute"` // Declares whether the property definition translates to an attribute instead of an element.
-	Wrapped   bool   `json:"wrapped,omitempty" yaml:"wrapped"`     // Applies to an array schema; adds a wrapping element.
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`           // Replaces the name of the element/attribute used for the described schema property.
+	Namespace  string     `json:"namespace,omitempty" yaml:"namespace,omitempty"` // The URL of the namespace definition.
+	Prefix     string     `json:"prefix,omitempty" yaml:"prefix,omitempty"`       // The prefix to be used for the name.
+	Attribute  bool       `json:"attribute,omitempty" yaml:"attribute,omitempty"` // Declares whether the property definition translates to an attribute instead of an element.
+	Wrapped    bool       `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`     // Applies to an array schema; adds a wrapping element.
+	Extensions Extensions `json:"-" yaml:"-"`                                     // Specification extensions (x- properties) of the object.
+}
+
+// Discriminator tells which schema of a composition describes a value, by the value of one of its properties.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName" yaml:"propertyName"`           // The name of the property that holds the discriminating value.
+	Mapping      map[string]string `json:"mapping,omitempty" yaml:"mapping,omitempty"` // Maps discriminating values to schema names or references.
+	Extensions   Extensions        `json:"-" yaml:"-"`                                 // Specification extensions (x- properties) of the object.
+
+	propertyOnly bool // Whether the discriminator is written as in Swagger 2.0, as the property name alone.
+}
+
+// ref returns the reference a mapping value stands for: the value itself, or the schema
+// component it names when it is a plain schema name.
+func (d *Discriminator) ref(value string) string {
+	if strings.ContainsAny(value, "#/") {
+		return value
+	}
+	return "#/components/schemas/" + pointerEscape(value)
+}
+
+// IsNullable reports whether the schema allows null, through Nullable (3.0) or a "null" type (3.1).
+func (s *Schema) IsNullable() bool {
+	return s.Nullable || s.Type.Includes("null")
+}
+
+// Types is the type of a schema. OpenAPI 3.0 allows a single type, OpenAPI 3.1 a list of types.
+type Types []string
+
+// Includes reports whether t is one of the types.
+func (t Types) Includes(name string) bool {
+	for _, v := range t {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the types separated by commas.
+func (t Types) String() string {
+	return strings.Join(t, ",")
+}
+
+// UnmarshalJSON decodes a single type or a list of types.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// MarshalJSON encodes a single type as a string and several types as a list.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalYAML decodes a single type or a list of types.
+func (t *Types) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = Types{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// MarshalYAML encodes a single type as a string and several types as a list.
+func (t Types) MarshalYAML() (interface{}, error) {
+	if len(t) == 1 {
+		return t[0], nil
+	}
+	return []string(t), nil
+}
+
+// ExclusiveBound holds exclusiveMinimum or exclusiveMaximum, which is a boolean modifier of
+// minimum/maximum in OpenAPI 3.0 and a number in OpenAPI 3.1.
+type ExclusiveBound struct {
+	Exclusive bool     // Whether minimum/maximum is exclusive (3.0).
+	Value     *float64 // The exclusive limit (3.1).
+}
+
+// UnmarshalJSON decodes a boolean or a number.
+func (b *ExclusiveBound) UnmarshalJSON(data []byte) error {
+	*b = ExclusiveBound{}
+	if err := json.Unmarshal(data, &b.Exclusive); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, &b.Value)
+}
+
+// MarshalJSON encodes the number when set and the boolean otherwise.
+func (b ExclusiveBound) MarshalJSON() ([]byte, error) {
+	if b.Value != nil {
+		return json.Marshal(*b.Value)
+	}
+	return json.Marshal(b.Exclusive)
+}
+
+// UnmarshalYAML decodes a boolean or a number.
+func (b *ExclusiveBound) UnmarshalYAML(node *yaml.Node) error {
+	*b = ExclusiveBound{}
+	if err := node.Decode(&b.Exclusive); err == nil {
+		return nil
+	}
+	return node.Decode(&b.Value)
+}
+
+// MarshalYAML encodes the number when set and the boolean otherwise.
+func (b ExclusiveBound) MarshalYAML() (interface{}, error) {
+	if b.Value != nil {
+		return *b.Value, nil
+	}
+	return b.Exclusive, nil
+}
+
+// exclusive reports whether a 3.0 exclusiveMinimum or exclusiveMaximum flag is set.
+func (b *ExclusiveBound) exclusive() bool {
+	return b != nil && b.Exclusive
+}
+
+// limit returns the 3.1 exclusiveMinimum or exclusiveMaximum value, if any.
+func (b *ExclusiveBound) limit() (float64, bool) {
+	if b == nil || b.Value == nil {
+		return 0, false
+	}
+	return *b.Value, true
 }
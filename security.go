@@ -0,0 +1,297 @@
+package oas
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Credentials are the credentials a request presents for a security scheme.
+type Credentials struct {
+	Scheme       string              // The name of the security scheme in the document.
+	Type         string              // The type of the security scheme, e.g. "apiKey" or "http".
+	Token        string              // The API key, or the token of a bearer, oauth2 or openIdConnect scheme.
+	Username     string              // The user name of an HTTP basic scheme.
+	Password     string              // The password of an HTTP basic scheme.
+	Certificates []*x509.Certificate // The client certificates of a mutualTLS scheme.
+}
+
+// Principal is the identity a Verifier establishes from credentials.
+type Principal struct {
+	Subject string      // The identifier of the authenticated party.
+	Scopes  []string    // The scopes or roles granted to the principal.
+	Value   interface{} // Any data the verifier wants to pass on, such as token claims.
+}
+
+// Verifier checks the credentials presented for a security scheme and returns the principal
+// they identify. It returns an error when the credentials are invalid.
+type Verifier func(r *http.Request, c *Credentials) (*Principal, error)
+
+// Authenticator enforces the security requirements of a document on incoming requests.
+type Authenticator struct {
+	Document *OpenAPI
+
+	verifiers map[string]Verifier
+}
+
+// NewAuthenticator returns an Authenticator for the security requirements of o.
+func NewAuthenticator(o *OpenAPI) *Authenticator {
+	return &Authenticator{Document: o, verifiers: map[string]Verifier{}}
+}
+
+// Verify registers the verifier of the security scheme with the given name. Requirements
+// naming a scheme without a verifier are never satisfied.
+func (a *Authenticator) Verify(scheme string, v Verifier) {
+	a.verifiers[scheme] = v
+}
+
+// AuthResult is the outcome of a successful authentication.
+type AuthResult struct {
+	Requirement SecurityRequirement   // The requirement that was satisfied; empty for anonymous access.
+	Principals  map[string]*Principal // The principals established, keyed by security scheme name.
+}
+
+// AuthError is returned when a request does not satisfy any security requirement.
+type AuthError struct {
+	Status     int      // http.StatusUnauthorized, or http.StatusForbidden when valid credentials lack scopes.
+	Challenges []string // The WWW-Authenticate challenges of the HTTP schemes that were tried.
+	Err        error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%d %s: %v", e.Status, http.StatusText(e.Status), e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+var errNoCredentials = errors.New("no credentials")
+
+// Requirements returns the security requirements of op: its own when it declares any,
+// including an empty list that disables security, and those of the document otherwise.
+func (a *Authenticator) Requirements(op *Operation) []*SecurityRequirement {
+	if op != nil && op.Security != nil {
+		return op.Security
+	}
+	return a.Document.Security
+}
+
+// Authenticate checks r against the security requirements of op. The requirements are
+// alternatives: the first one whose schemes all verify, with the scopes it lists, is
+// returned. Failures are returned as an *AuthError.
+func (a *Authenticator) Authenticate(r *http.Request, op *Operation) (*AuthResult, error) {
+	requirements := a.Requirements(op)
+	if len(requirements) == 0 {
+		return &AuthResult{Requirement: SecurityRequirement{}, Principals: map[string]*Principal{}}, nil
+	}
+
+	var errs []error
+	forbidden := false
+	var challenges []string
+	for _, requirement := range requirements {
+		if requirement == nil {
+			continue
+		}
+		result, scopesMissing, err := a.satisfy(r, *requirement, &challenges)
+		if err == nil {
+			return result, nil
+		}
+		forbidden = forbidden || scopesMissing
+		errs = append(errs, err)
+	}
+	status := http.StatusUnauthorized
+	if forbidden {
+		status = http.StatusForbidden
+	}
+	return nil, &AuthError{Status: status, Challenges: challenges, Err: errors.Join(errs...)}
+}
+
+// satisfy verifies every scheme of a requirement. scopesMissing is set when the credentials
+// were valid but lacked a required scope.
+func (a *Authenticator) satisfy(r *http.Request, requirement SecurityRequirement, challenges *[]string) (result *AuthResult, scopesMissing bool, err error) {
+	for _, name := range sortedKeys(requirement) {
+		if scheme, err := a.scheme(name); err == nil {
+			if challenge := challengeOf(scheme); challenge != "" && !contains(*challenges, challenge) {
+				*challenges = append(*challenges, challenge)
+			}
+		}
+	}
+	result = &AuthResult{Requirement: requirement, Principals: map[string]*Principal{}}
+	for _, name := range sortedKeys(requirement) {
+		scheme, err := a.scheme(name)
+		if err != nil {
+			return nil, false, err
+		}
+		credentials, err := credentialsOf(r, name, scheme)
+		if err != nil {
+			return nil, false, fmt.Errorf("security scheme '%s': %w", name, err)
+		}
+		verify := a.verifiers[name]
+		if verify == nil {
+			return nil, false, fmt.Errorf("security scheme '%s': no verifier registered", name)
+		}
+		principal, err := verify(r, credentials)
+		if err != nil {
+			return nil, false, fmt.Errorf("security scheme '%s': %w", name, err)
+		}
+		if principal == nil {
+			principal = &Principal{}
+		}
+		if missing := missingScopes(requirement[name], principal.Scopes); len(missing) > 0 {
+			return nil, true, fmt.Errorf("security scheme '%s': missing scopes %s", name, strings.Join(missing, ", "))
+		}
+		result.Principals[name] = principal
+	}
+	return result, false, nil
+}
+
+// scheme returns the security scheme with the given name, following its reference.
+func (a *Authenticator) scheme(name string) (*SecurityScheme, error) {
+	if a.Document.Components == nil || a.Document.Components.SecuritySchemes[name] == nil {
+		return nil, fmt.Errorf("security scheme '%s' is not defined", name)
+	}
+	scheme := a.Document.Components.SecuritySchemes[name]
+	if scheme.Ref != "" {
+		target, err := a.Document.resolveRef(scheme.Ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved, ok := target.(*SecurityScheme)
+		if !ok {
+			return nil, fmt.Errorf("reference '%s' is not a security scheme", scheme.Ref)
+		}
+		scheme = resolved
+	}
+	return scheme, nil
+}
+
+// credentialsOf extracts the credentials of a scheme from r.
+func credentialsOf(r *http.Request, name string, scheme *SecurityScheme) (*Credentials, error) {
+	c := &Credentials{Scheme: name, Type: scheme.Type}
+	switch scheme.Type {
+	case "apiKey":
+		switch scheme.In {
+		case "header":
+			c.Token = r.Header.Get(scheme.Name)
+		case "query":
+			c.Token = r.URL.Query().Get(scheme.Name)
+		case "cookie":
+			if cookie, err := r.Cookie(scheme.Name); err == nil {
+				c.Token = cookie.Value
+			}
+		default:
+			return nil, fmt.Errorf("unsupported API key location '%s'", scheme.In)
+		}
+		if c.Token == "" {
+			return nil, errNoCredentials
+		}
+	case "http":
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			username, password, ok := r.BasicAuth()
+			if !ok {
+				return nil, errNoCredentials
+			}
+			c.Username, c.Password = username, password
+			break
+		}
+		token, ok := authorization(r, scheme.Scheme)
+		if !ok {
+			return nil, errNoCredentials
+		}
+		c.Token = token
+	case "oauth2", "openIdConnect":
+		token, ok := authorization(r, "Bearer")
+		if !ok {
+			return nil, errNoCredentials
+		}
+		c.Token = token
+	case "mutualTLS":
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return nil, errNoCredentials
+		}
+		c.Certificates = r.TLS.PeerCertificates
+	default:
+		return nil, fmt.Errorf("unsupported security scheme type '%s'", scheme.Type)
+	}
+	return c, nil
+}
+
+// authorization returns the credentials of the Authorization header of r when it uses scheme.
+func authorization(r *http.Request, scheme string) (string, bool) {
+	header := r.Header.Get("Authorization")
+	prefix, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(prefix, scheme) {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// challengeOf returns the WWW-Authenticate challenge of a scheme sent in the Authorization header.
+func challengeOf(scheme *SecurityScheme) string {
+	switch scheme.Type {
+	case "http":
+		if scheme.Scheme == "" {
+			return ""
+		}
+		return strings.ToUpper(scheme.Scheme[:1]) + strings.ToLower(scheme.Scheme[1:])
+	case "oauth2", "openIdConnect":
+		return "Bearer"
+	}
+	return ""
+}
+
+// missingScopes returns the required scopes that are not granted, sorted.
+func missingScopes(required, granted []string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+type authResultKey struct{}
+
+// AuthResultFromContext returns the result stored by Authenticator.Middleware.
+func AuthResultFromContext(ctx context.Context) (*AuthResult, bool) {
+	result, ok := ctx.Value(authResultKey{}).(*AuthResult)
+	return result, ok
+}
+
+// Middleware authenticates every request against the operation operation returns for it,
+// or the document's requirements when it returns nil. Requests that fail are answered with
+// 401 or 403; the others reach next with the AuthResult in their context.
+func (a *Authenticator) Middleware(operation func(r *http.Request) *Operation) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var op *Operation
+			if operation != nil {
+				op = operation(r)
+			}
+			result, err := a.Authenticate(r, op)
+			if err != nil {
+				status := http.StatusUnauthorized
+				var authErr *AuthError
+				if errors.As(err, &authErr) {
+					status = authErr.Status
+					if status == http.StatusUnauthorized {
+						for _, challenge := range authErr.Challenges {
+							w.Header().Add("WWW-Authenticate", challenge)
+						}
+					}
+				}
+				http.Error(w, http.StatusText(status), status)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authResultKey{}, result)))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package oas
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// securityFixture returns an Authenticator whose document requires an API key together
+// with basic credentials, or an OAuth2 token with the read scope.
+func securityFixture() *Authenticator {
+	doc := &OpenAPI{
+		Components: &Components{SecuritySchemes: map[string]*SecurityScheme{
+			"key":   {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			"basic": {Type: "http", Scheme: "basic"},
+			"oauth": {Type: "oauth2"},
+		}},
+		Security: []*SecurityRequirement{
+			{"key": {}, "basic": {}},
+			{"oauth": {"read"}},
+		},
+	}
+	a := NewAuthenticator(doc)
+	a.Verify("key", func(r *http.Request, c *Credentials) (*Principal, error) {
+		if c.Token != "k" {
+			return nil, errors.New("unknown key")
+		}
+		return &Principal{Subject: "client"}, nil
+	})
+	a.Verify("basic", func(r *http.Request, c *Credentials) (*Principal, error) {
+		if c.Username != "ada" || c.Password != "secret" {
+			return nil, errors.New("wrong password")
+		}
+		return &Principal{Subject: c.Username}, nil
+	})
+	a.Verify("oauth", func(r *http.Request, c *Credentials) (*Principal, error) {
+		scopes, ok := map[string][]string{"reader": {"read"}, "writer": {"write"}}[c.Token]
+		if !ok {
+			return nil, errors.New("unknown token")
+		}
+		return &Principal{Subject: c.Token, Scopes: scopes}, nil
+	})
+	return a
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		basic       []string
+		op          *Operation
+		status      int      // The status of the AuthError, or 0 when authentication succeeds.
+		requirement []string // The schemes of the satisfied requirement.
+	}{
+		{name: "key and basic", header: http.Header{"X-Api-Key": {"k"}}, basic: []string{"ada", "secret"}, requirement: []string{"basic", "key"}},
+		{name: "key without basic", header: http.Header{"X-Api-Key": {"k"}}, status: http.StatusUnauthorized},
+		{name: "basic without key", basic: []string{"ada", "secret"}, status: http.StatusUnauthorized},
+		{name: "key with a wrong password", header: http.Header{"X-Api-Key": {"k"}}, basic: []string{"ada", "guess"}, status: http.StatusUnauthorized},
+		{name: "token with the scope", header: http.Header{"Authorization": {"Bearer reader"}}, requirement: []string{"oauth"}},
+		{name: "token without the scope", header: http.Header{"Authorization": {"Bearer writer"}}, status: http.StatusForbidden},
+		{name: "unknown token", header: http.Header{"Authorization": {"Bearer nobody"}}, status: http.StatusUnauthorized},
+		{name: "no credentials", status: http.StatusUnauthorized},
+		{
+			name:   "missing scope wins over another failed alternative",
+			header: http.Header{"X-Api-Key": {"k"}, "Authorization": {"Bearer writer"}},
+			status: http.StatusForbidden,
+		},
+		{name: "operation without security", op: &Operation{Security: []*SecurityRequirement{}}, requirement: []string{}},
+		{
+			name:        "operation requirements replace the document's",
+			header:      http.Header{"Authorization": {"Bearer writer"}},
+			op:          &Operation{Security: []*SecurityRequirement{{"oauth": {"write"}}}},
+			requirement: []string{"oauth"},
+		},
+		{
+			name:   "operation requirements do not fall back to the document's",
+			header: http.Header{"X-Api-Key": {"k"}},
+			basic:  []string{"ada", "secret"},
+			op:     &Operation{Security: []*SecurityRequirement{{"oauth": {"write"}}}},
+			status: http.StatusUnauthorized,
+		},
+	}
+	a := securityFixture()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, values := range test.header {
+				r.Header[name] = values
+			}
+			if test.basic != nil {
+				r.SetBasicAuth(test.basic[0], test.basic[1])
+			}
+			result, err := a.Authenticate(r, test.op)
+			if test.status != 0 {
+				var authErr *AuthError
+				if !errors.As(err, &authErr) || authErr.Status != test.status {
+					t.Fatalf("Authenticate() = %v, want status %d", err, test.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() = %v, want no error", err)
+			}
+			if got := sortedKeys(result.Requirement); !reflect.DeepEqual(got, test.requirement) {
+				t.Errorf("requirement = %v, want %v", got, test.requirement)
+			}
+			if len(result.Principals) != len(test.requirement) {
+				t.Errorf("principals = %v, want one for each of %v", result.Principals, test.requirement)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	handler := securityFixture().Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok := AuthResultFromContext(r.Context())
+		if !ok {
+			t.Error("no AuthResult in the request context")
+			return
+		}
+		w.Write([]byte(result.Principals["oauth"].Subject))
+	}))
+	tests := []struct {
+		authorization string
+		status        int
+		challenges    []string
+	}{
+		{"", http.StatusUnauthorized, []string{"Basic", "Bearer"}},
+		{"Bearer writer", http.StatusForbidden, nil},
+		{"Bearer reader", http.StatusOK, nil},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.authorization != "" {
+			r.Header.Set("Authorization", test.authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != test.status {
+			t.Errorf("%q: status = %d, want %d", test.authorization, w.Code, test.status)
+		}
+		if got := w.Header().Values("WWW-Authenticate"); !reflect.DeepEqual(got, test.challenges) {
+			t.Errorf("%q: WWW-Authenticate = %v, want %v", test.authorization, got, test.challenges)
+		}
+		if test.status == http.StatusOK && strings.TrimSpace(w.Body.String()) != "reader" {
+			t.Errorf("%q: body = %q, want reader", test.authorization, w.Body.String())
+		}
+	}
+}
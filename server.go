@@ -0,0 +1,200 @@
+package oas
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// serverVariables splits the URL template of a server into its literal parts and the names
+// of its variables: literals[i] precedes names[i], and the last literal ends the template.
+func serverVariables(template string) (literals, names []string, err error) {
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			if strings.IndexByte(template, '}') >= 0 {
+				return nil, nil, errors.New("server URL has an unmatched '}'")
+			}
+			return append(literals, template), names, nil
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return nil, nil, errors.New("server URL has an unclosed '{'")
+		}
+		literals = append(literals, template[:start])
+		names = append(names, template[start+1:start+end])
+		template = template[start+end+1:]
+	}
+}
+
+// Expand returns the URL of the server with its variables replaced by the values of
+// overrides, or by their default values. Values must be one of the Enum of their variable
+// when it has one, and every variable of the URL must be defined by the server.
+func (s *Server) Expand(overrides map[string]string) (string, error) {
+	literals, names, err := serverVariables(s.URL)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range sortedKeys(overrides) {
+		if s.Variables[name] == nil {
+			return "", fmt.Errorf("server '%s' has no variable '%s'", s.URL, name)
+		}
+	}
+	var b strings.Builder
+	for i, name := range names {
+		b.WriteString(literals[i])
+		variable := s.Variables[name]
+		if variable == nil {
+			return "", fmt.Errorf("server variable '%s' is not defined", name)
+		}
+		value, ok := overrides[name]
+		if !ok {
+			value = variable.Default
+		}
+		if len(variable.Enum) > 0 && !contains(variable.Enum, value) {
+			return "", fmt.Errorf("server variable '%s': value '%s' is not one of %s", name, value, strings.Join(variable.Enum, ", "))
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(literals[len(literals)-1])
+	return b.String(), nil
+}
+
+// EffectiveServers returns the servers of an operation: those of op when it declares any,
+// otherwise those of path, otherwise those of the document. A document without servers is
+// served from "/". path and op may be nil.
+func (o *OpenAPI) EffectiveServers(path *Path, op *Operation) []*Server {
+	switch {
+	case op != nil && len(op.Servers) > 0:
+		return op.Servers
+	case path != nil && len(path.Servers) > 0:
+		return path.Servers
+	case len(o.Servers) > 0:
+		return o.Servers
+	}
+	return []*Server{{URL: "/"}}
+}
+
+// ServerMatch is a server matching a request URL.
+type ServerMatch struct {
+	Server    *Server
+	Variables map[string]string // The values of the variables of the server, bound by the URL or defaulted.
+	Path      string            // The escaped path of the URL below the server URL, starting with "/".
+}
+
+// MatchServer returns the server among the EffectiveServers of path and op that serves the
+// absolute URL rawURL. Variables match any text within a host label sequence or a path
+// segment, or one of their Enum values; relative server URLs are matched against the path
+// of rawURL only. When several servers match, the one matching the longest prefix of the
+// URL wins, then the one declared first. Schemes and hosts compare case-insensitively.
+func (o *OpenAPI) MatchServer(rawURL string, path *Path, op *Operation) (*ServerMatch, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, false
+	}
+	var best *ServerMatch
+	bestLength := -1
+	for _, server := range o.EffectiveServers(path, op) {
+		if server == nil {
+			continue
+		}
+		match, length, ok := matchServer(server, u)
+		if ok && length > bestLength {
+			best, bestLength = match, length
+		}
+	}
+	return best, best != nil
+}
+
+// matchServer matches u against the URL template of a server, returning the length of the
+// part of the URL the server covers.
+func matchServer(server *Server, u *url.URL) (*ServerMatch, int, bool) {
+	template := strings.TrimSuffix(server.URL, "/")
+	literals, names, err := serverVariables(template)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	// The path is matched escaped, so that an encoded "/" does not separate segments. The
+	// scheme and host are case-insensitive: they are lowercased on both sides.
+	subject := u.EscapedPath()
+	hostEnd := 0
+	if start := strings.Index(template, "://"); start >= 0 {
+		subject = strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + subject
+		hostEnd = start + len("://")
+	} else if strings.HasPrefix(template, "//") {
+		subject = "//" + strings.ToLower(u.Host) + subject
+		hostEnd = len("//")
+	}
+	if hostEnd > 0 {
+		if end := strings.IndexByte(template[hostEnd:], '/'); end >= 0 {
+			hostEnd += end
+		} else {
+			hostEnd = len(template)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	offset := 0
+	for i, name := range names {
+		b.WriteString(regexp.QuoteMeta(lowerPrefix(literals[i], hostEnd-offset)))
+		offset += len(literals[i])
+		inHost := offset < hostEnd
+		offset += len(name) + len("{}")
+		variable := server.Variables[name]
+		if variable != nil && len(variable.Enum) > 0 {
+			alternatives := make([]string, len(variable.Enum))
+			for j, value := range variable.Enum {
+				if inHost {
+					value = strings.ToLower(value)
+				}
+				alternatives[j] = regexp.QuoteMeta(value)
+			}
+			b.WriteString("(" + strings.Join(alternatives, "|") + ")")
+			continue
+		}
+		b.WriteString("([^/?#]*)")
+	}
+	b.WriteString(regexp.QuoteMeta(lowerPrefix(literals[len(literals)-1], hostEnd-offset)))
+	b.WriteString("(/.*)?$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, 0, false
+	}
+	groups := re.FindStringSubmatch(subject)
+	if groups == nil {
+		return nil, 0, false
+	}
+
+	match := &ServerMatch{Server: server, Variables: map[string]string{}, Path: groups[len(groups)-1]}
+	for name, variable := range server.Variables {
+		if variable != nil {
+			match.Variables[name] = variable.Default
+		}
+	}
+	for i, name := range names {
+		value, err := url.PathUnescape(groups[i+1])
+		if err != nil {
+			value = groups[i+1]
+		}
+		match.Variables[name] = value
+	}
+	if match.Path == "" {
+		match.Path = "/"
+	}
+	return match, len(subject) - len(groups[len(groups)-1]), true
+}
+
+// lowerPrefix returns s with its first n bytes lowercased.
+func lowerPrefix(s string, n int) string {
+	switch {
+	case n <= 0:
+		return s
+	case n >= len(s):
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(s[:n]) + s[n:]
+}
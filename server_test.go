@@ -0,0 +1,94 @@
+package oas
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServerExpand(t *testing.T) {
+	server := &Server{
+		URL: "https://{region}.example.com/{version}",
+		Variables: map[string]*ServerVariable{
+			"region":  {Default: "eu", Enum: []string{"eu", "us"}},
+			"version": {Default: "v1"},
+		},
+	}
+	tests := []struct {
+		name      string
+		server    *Server
+		overrides map[string]string
+		want      string
+		err       string // A substring of the expected error, or empty when the URL expands.
+	}{
+		{name: "defaults", server: server, want: "https://eu.example.com/v1"},
+		{name: "overrides", server: server, overrides: map[string]string{"region": "us", "version": "v2"}, want: "https://us.example.com/v2"},
+		{name: "value not in enum", server: server, overrides: map[string]string{"region": "ap"}, err: "not one of eu, us"},
+		{name: "unknown override", server: server, overrides: map[string]string{"zone": "a"}, err: "has no variable 'zone'"},
+		{name: "undefined variable", server: &Server{URL: "https://{host}/"}, err: "'host' is not defined"},
+		{name: "unclosed brace", server: &Server{URL: "https://{host/"}, err: "unclosed '{'"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.server.Expand(test.overrides)
+			if test.err == "" && err != nil {
+				t.Errorf("Expand() = %v, want no error", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Errorf("Expand() = %v, want an error containing %q", err, test.err)
+			}
+			if got != test.want {
+				t.Errorf("Expand() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchServer(t *testing.T) {
+	doc := &OpenAPI{Servers: []*Server{
+		{URL: "https://API.Example.com/v1"},
+		{URL: "https://{tenant}.Example.com/{version}", Variables: map[string]*ServerVariable{
+			"tenant":  {Default: "acme", Enum: []string{"Acme", "Globex"}},
+			"version": {Default: "v2"},
+		}},
+		{URL: "/relative"},
+	}}
+	tests := []struct {
+		name      string
+		url       string
+		server    string // The URL of the matching server, or empty when none matches.
+		variables map[string]string
+		path      string
+	}{
+		{name: "literal host", url: "https://api.example.com/v1/pets", server: "https://API.Example.com/v1", variables: map[string]string{}, path: "/pets"},
+		{name: "mixed-case host", url: "HTTPS://API.Example.COM/v1/pets", server: "https://API.Example.com/v1", variables: map[string]string{}, path: "/pets"},
+		{name: "enum in host", url: "https://GLOBEX.example.com/v3/pets", server: "https://{tenant}.Example.com/{version}", variables: map[string]string{"tenant": "globex", "version": "v3"}, path: "/pets"},
+		{name: "enum mismatch", url: "https://initech.example.com/v3/pets"},
+		{name: "path stays case-sensitive", url: "https://acme.example.com/V1/pets", server: "https://{tenant}.Example.com/{version}", variables: map[string]string{"tenant": "acme", "version": "V1"}, path: "/pets"},
+		{name: "server root", url: "https://api.example.com/v1", server: "https://API.Example.com/v1", variables: map[string]string{}, path: "/"},
+		{name: "relative server", url: "https://other.test/relative/pets", server: "/relative", variables: map[string]string{}, path: "/pets"},
+		{name: "escaped slash is not a separator", url: "https://api.example.com/v1%2Fpets"},
+		{name: "escaped slash kept in path", url: "https://api.example.com/v1/a%2Fb", server: "https://API.Example.com/v1", variables: map[string]string{}, path: "/a%2Fb"},
+		{name: "escaped slash in variable", url: "https://acme.example.com/v%2F2/pets", server: "https://{tenant}.Example.com/{version}", variables: map[string]string{"tenant": "acme", "version": "v/2"}, path: "/pets"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match, ok := doc.MatchServer(test.url, nil, nil)
+			if ok != (test.server != "") {
+				t.Fatalf("MatchServer() ok = %v, want %v (match %+v)", ok, test.server != "", match)
+			}
+			if !ok {
+				return
+			}
+			if match.Server.URL != test.server {
+				t.Errorf("server = %q, want %q", match.Server.URL, test.server)
+			}
+			if !reflect.DeepEqual(match.Variables, test.variables) {
+				t.Errorf("variables = %v, want %v", match.Variables, test.variables)
+			}
+			if match.Path != test.path {
+				t.Errorf("path = %q, want %q", match.Path, test.path)
+			}
+		})
+	}
+}
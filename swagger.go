@@ -0,0 +1,652 @@
+package oas
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Swagger represents the root document object of a Swagger 2.0 specification
+type Swagger struct {
+	Swagger             string                            `json:"swagger" yaml:"swagger"`                                             // REQUIRED. The version of the specification, "2.0".
+	Info                *Info                             `json:"info" yaml:"info"`                                                   // REQUIRED. Provides metadata about the API.
+	Host                string                            `json:"host,omitempty" yaml:"host,omitempty"`                               // The host (name or ip) serving the API.
+	BasePath            string                            `json:"basePath,omitempty" yaml:"basePath,omitempty"`                       // The base path on which the API is served, relative to the host.
+	Schemes             []string                          `json:"schemes,omitempty" yaml:"schemes,omitempty"`                         // The transfer protocols of the API.
+	Consumes            []string                          `json:"consumes,omitempty" yaml:"consumes,omitempty"`                       // The MIME types the APIs can consume.
+	Produces            []string                          `json:"produces,omitempty" yaml:"produces,omitempty"`                       // The MIME types the APIs can produce.
+	Paths               map[string]*SwaggerPath           `json:"paths" yaml:"paths"`                                                 // REQUIRED. The available paths and operations for the API.
+	Definitions         map[string]*Schema                `json:"definitions,omitempty" yaml:"definitions,omitempty"`                 // The data types produced and consumed by operations.
+	Parameters          map[string]*SwaggerParameter      `json:"parameters,omitempty" yaml:"parameters,omitempty"`                   // Parameters that can be used across operations.
+	Responses           map[string]*SwaggerResponse       `json:"responses,omitempty" yaml:"responses,omitempty"`                     // Responses that can be used across operations.
+	SecurityDefinitions map[string]*SwaggerSecurityScheme `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"` // Security schemes available to be used across the specification.
+	Security            []*SecurityRequirement            `json:"security,omitempty" yaml:"security,omitempty"`                       // The security schemes applied to the API as a whole.
+	Tags                []*Tag                            `json:"tags,omitempty" yaml:"tags,omitempty"`                               // A list of tags used by the specification with additional metadata.
+	ExternalDocs        *ExternalDocumentation            `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`               // Additional external documentation.
+	Extensions          Extensions                        `json:"-" yaml:"-"`                                                         // Specification extensions (x- properties) of the object.
+}
+
+// SwaggerPath represents a path item object in Swagger 2.0
+type SwaggerPath struct {
+	Ref        string              `json:"$ref,omitempty" yaml:"$ref,omitempty"`             // Allows for an external definition of this path item.
+	Get        *SwaggerOperation   `json:"get,omitempty" yaml:"get,omitempty"`               // A definition of a GET operation on this path.
+	Put        *SwaggerOperation   `json:"put,omitempty" yaml:"put,omitempty"`               // A definition of a PUT operation on this path.
+	Post       *SwaggerOperation   `json:"post,omitempty" yaml:"post,omitempty"`             // A definition of a POST operation on this path.
+	Delete     *SwaggerOperation   `json:"delete,omitempty" yaml:"delete,omitempty"`         // A definition of a DELETE operation on this path.
+	Options    *SwaggerOperation   `json:"options,omitempty" yaml:"options,omitempty"`       // A definition of an OPTIONS operation on this path.
+	Head       *SwaggerOperation   `json:"head,omitempty" yaml:"head,omitempty"`             // A definition of a HEAD operation on this path.
+	Patch      *SwaggerOperation   `json:"patch,omitempty" yaml:"patch,omitempty"`           // A definition of a PATCH operation on this path.
+	Parameters []*SwaggerParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"` // Parameters applicable for all the operations on this path.
+	Extensions Extensions          `json:"-" yaml:"-"`                                       // Specification extensions (x- properties) of the object.
+}
+
+// SwaggerOperation represents an operation object in Swagger 2.0
+type SwaggerOperation struct {
+	Tags         []string                    `json:"tags,omitempty" yaml:"tags,omitempty"`                 // A list of tags for API documentation control.
+	Summary      string                      `json:"summary,omitempty" yaml:"summary,omitempty"`           // A short summary of what the operation does.
+	Description  string                      `json:"description,omitempty" yaml:"description,omitempty"`   // A verbose explanation of the operation behavior.
+	ExternalDocs *ExternalDocumentation      `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"` // Additional external documentation for this operation.
+	OperationID  string                      `json:"operationId,omitempty" yaml:"operationId,omitempty"`   // Unique string used to identify the operation.
+	Consumes     []string                    `json:"consumes,omitempty" yaml:"consumes,omitempty"`         // The MIME types the operation can consume.
+	Produces     []string                    `json:"produces,omitempty" yaml:"produces,omitempty"`         // The MIME types the operation can produce.
+	Parameters   []*SwaggerParameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`     // A list of parameters applicable for this operation.
+	Responses    map[string]*SwaggerResponse `json:"responses" yaml:"responses"`                           // REQUIRED. The list of possible responses.
+	Schemes      []string                    `json:"schemes,omitempty" yaml:"schemes,omitempty"`           // The transfer protocols of the operation.
+	Deprecated   bool                        `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`     // Declares this operation to be deprecated.
+	Security     []*SecurityRequirement      `json:"security,omitempty" yaml:"security,omitempty"`         // The security schemes applied to this operation.
+	Extensions   Extensions                  `json:"-" yaml:"-"`                                           // Specification extensions (x- properties) of the object.
+}
+
+// SwaggerItems holds the type and validations of a non-body parameter, header or array item in Swagger 2.0
+type SwaggerItems struct {
+	Type             string        `json:"type,omitempty" yaml:"type,omitempty"`                         // The type of the value: string, number, integer, boolean, array or file.
+	Format           string        `json:"format,omitempty" yaml:"format,omitempty"`                     // The extending format for the type.
+	Items            *SwaggerItems `json:"items,omitempty" yaml:"items,omitempty"`                       // The type of items in an array.
+	CollectionFormat string        `json:"collectionFormat,omitempty" yaml:"collectionFormat,omitempty"` // The format of an array: csv, ssv, tsv, pipes or multi.
+	Default          interface{}   `json:"default,omitempty" yaml:"default,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum bool          `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum bool          `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	MaxLength        *int          `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinLength        *int          `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern          *string       `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	MaxItems         *int          `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinItems         *int          `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	UniqueItems      bool          `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	MultipleOf       *float64      `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+}
+
+// SwaggerParameter represents a parameter object in Swagger 2.0
+type SwaggerParameter struct {
+	SwaggerItems    `yaml:",inline"` // The type of non-body parameters.
+	Ref             string           `json:"$ref,omitempty" yaml:"$ref,omitempty"`                       // Allows for a reference to a parameter defined at the document level.
+	Name            string           `json:"name,omitempty" yaml:"name,omitempty"`                       // REQUIRED. The name of the parameter.
+	In              string           `json:"in,omitempty" yaml:"in,omitempty"`                           // REQUIRED. The location of the parameter: query, header, path, formData or body.
+	Description     string           `json:"description,omitempty" yaml:"description,omitempty"`         // A brief description of the parameter.
+	Required        bool             `json:"required,omitempty" yaml:"required,omitempty"`               // Determines whether this parameter is mandatory.
+	Schema          *Schema          `json:"schema,omitempty" yaml:"schema,omitempty"`                   // REQUIRED for body parameters. The schema of the body.
+	AllowEmptyValue bool             `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"` // Sets the ability to pass empty-valued parameters.
+	Extensions      Extensions       `json:"-" yaml:"-"`                                                 // Specification extensions (x- properties) of the object.
+}
+
+// SwaggerHeader represents a header object in Swagger 2.0
+type SwaggerHeader struct {
+	SwaggerItems `yaml:",inline"` // The type of the header.
+	Description  string           `json:"description,omitempty" yaml:"description,omitempty"` // A short description of the header.
+}
+
+// SwaggerResponse represents a response object in Swagger 2.0
+type SwaggerResponse struct {
+	Ref         string                    `json:"$ref,omitempty" yaml:"$ref,omitempty"`         // Allows for a reference to a response defined at the document level.
+	Description string                    `json:"description" yaml:"description"`               // REQUIRED. A short description of the response.
+	Schema      *Schema                   `json:"schema,omitempty" yaml:"schema,omitempty"`     // The structure of the response body.
+	Headers     map[string]*SwaggerHeader `json:"headers,omitempty" yaml:"headers,omitempty"`   // The headers sent with the response.
+	Examples    map[string]interface{}    `json:"examples,omitempty" yaml:"examples,omitempty"` // Examples of the response body keyed by MIME type.
+	Extensions  Extensions                `json:"-" yaml:"-"`                                   // Specification extensions (x- properties) of the object.
+}
+
+// SwaggerSecurityScheme represents a security scheme object in Swagger 2.0
+type SwaggerSecurityScheme struct {
+	Type             string            `json:"type" yaml:"type"`                                             // REQUIRED. The type of the security scheme: basic, apiKey or oauth2.
+	Description      string            `json:"description,omitempty" yaml:"description,omitempty"`           // A short description for security scheme.
+	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`                         // The name of the header or query parameter of an apiKey.
+	In               string            `json:"in,omitempty" yaml:"in,omitempty"`                             // The location of an apiKey: query or header.
+	Flow             string            `json:"flow,omitempty" yaml:"flow,omitempty"`                         // The OAuth2 flow: implicit, password, application or accessCode.
+	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"` // The OAuth2 authorization URL.
+	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`                 // The OAuth2 token URL.
+	Scopes           map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`                     // The available OAuth2 scopes.
+	Extensions       Extensions        `json:"-" yaml:"-"`                                                   // Specification extensions (x- properties) of the object.
+}
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a Swagger with its extensions.
+func (s *Swagger) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, s) }
+func (s Swagger) MarshalJSON() ([]byte, error)         { return marshalJSONObject(s) }
+func (s *Swagger) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, s) }
+func (s Swagger) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(s) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a SwaggerPath with its extensions.
+func (p *SwaggerPath) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, p) }
+func (p SwaggerPath) MarshalJSON() ([]byte, error)         { return marshalJSONObject(p) }
+func (p *SwaggerPath) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, p) }
+func (p SwaggerPath) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(p) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a SwaggerOperation with its extensions.
+func (o *SwaggerOperation) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, o) }
+func (o SwaggerOperation) MarshalJSON() ([]byte, error)         { return marshalJSONObject(o) }
+func (o *SwaggerOperation) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, o) }
+func (o SwaggerOperation) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(o) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a SwaggerParameter with its extensions.
+func (p *SwaggerParameter) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, p) }
+func (p SwaggerParameter) MarshalJSON() ([]byte, error)         { return marshalJSONObject(p) }
+func (p *SwaggerParameter) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, p) }
+func (p SwaggerParameter) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(p) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a SwaggerResponse with its extensions.
+func (r *SwaggerResponse) UnmarshalJSON(data []byte) error     { return unmarshalJSONObject(data, r) }
+func (r SwaggerResponse) MarshalJSON() ([]byte, error)         { return marshalJSONObject(r) }
+func (r *SwaggerResponse) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAMLObject(node, r) }
+func (r SwaggerResponse) MarshalYAML() (interface{}, error)    { return marshalYAMLObject(r) }
+
+// UnmarshalJSON, MarshalJSON, UnmarshalYAML and MarshalYAML decode and encode a SwaggerSecurityScheme with its extensions.
+func (s *SwaggerSecurityScheme) UnmarshalJSON(data []byte) error { return unmarshalJSONObject(data, s) }
+func (s SwaggerSecurityScheme) MarshalJSON() ([]byte, error)     { return marshalJSONObject(s) }
+func (s *SwaggerSecurityScheme) UnmarshalYAML(node *yaml.Node) error {
+	return unmarshalYAMLObject(node, s)
+}
+func (s SwaggerSecurityScheme) MarshalYAML() (interface{}, error) { return marshalYAMLObject(s) }
+
+// ConversionWarning describes something a conversion between specification versions could not carry over exactly.
+type ConversionWarning struct {
+	Pointer string // The JSON Pointer of the affected node in the source document.
+	Message string // A description of what was changed or dropped.
+}
+
+func (w ConversionWarning) String() string {
+	return w.Pointer + ": " + w.Message
+}
+
+// NewSwagger parses a Swagger 2.0 document from JSON or YAML.
+func NewSwagger(bytes []byte) (*Swagger, error) {
+	swagger := &Swagger{}
+	if err := json.Unmarshal(bytes, swagger); err != nil {
+		if err := yaml.Unmarshal(bytes, swagger); err != nil {
+			return nil, err
+		}
+	}
+	if !strings.HasPrefix(swagger.Swagger, "2.") {
+		return nil, fmt.Errorf("unsupported swagger version '%s'", swagger.Swagger)
+	}
+	return swagger, nil
+}
+
+// NewOpenAPIFromSwagger parses a Swagger 2.0 document and converts it to OpenAPI 3.0.
+func NewOpenAPIFromSwagger(bytes []byte) (*OpenAPI, []ConversionWarning, error) {
+	swagger, err := NewSwagger(bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	openAPI, warnings := swagger.ToOpenAPI()
+	return openAPI, warnings, nil
+}
+
+// conversion collects the warnings of a conversion between specification versions.
+type conversion struct {
+	warnings []ConversionWarning
+}
+
+func (c *conversion) warn(pointer, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, ConversionWarning{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// swaggerConverter holds the state of a Swagger 2.0 to OpenAPI 3.0 conversion.
+type swaggerConverter struct {
+	conversion
+	swagger *Swagger
+}
+
+// ToOpenAPI converts the document to OpenAPI 3.0. Every $ref is rewritten to point into
+// the Components object, and anything that could not be converted exactly is reported.
+func (s *Swagger) ToOpenAPI() (*OpenAPI, []ConversionWarning) {
+	s = clone(s)
+	c := &swaggerConverter{swagger: s}
+	openAPI := &OpenAPI{
+		OpenAPIVersion: "3.0.3",
+		Info:           s.Info,
+		ExternalDocs:   s.ExternalDocs,
+		Tags:           s.Tags,
+		Security:       s.Security,
+		Servers:        c.servers("", s.Schemes),
+		Paths:          map[string]*Path{},
+		Components:     &Components{},
+		Extensions:     s.Extensions,
+	}
+
+	components := openAPI.Components
+	components.Schemas = s.Definitions
+	for _, name := range sortedKeys(s.Parameters) {
+		p := s.Parameters[name]
+		pointer := "/parameters/" + pointerEscape(name)
+		switch p.In {
+		case "body":
+			if components.RequestBodies == nil {
+				components.RequestBodies = map[string]*RequestBody{}
+			}
+			components.RequestBodies[name] = c.requestBody(pointer, p, s.Consumes)
+		case "formData":
+			c.warn(pointer, "form parameters cannot be shared in OpenAPI 3.0; they are inlined where referenced")
+		default:
+			if components.Parameters == nil {
+				components.Parameters = map[string]*Parameter{}
+			}
+			components.Parameters[name] = c.parameter(pointer, p)
+		}
+	}
+	for _, name := range sortedKeys(s.Responses) {
+		if components.Responses == nil {
+			components.Responses = map[string]*Response{}
+		}
+		components.Responses[name] = c.response("/responses/"+pointerEscape(name), s.Responses[name], s.Produces)
+	}
+	for _, name := range sortedKeys(s.SecurityDefinitions) {
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = map[string]*SecurityScheme{}
+		}
+		components.SecuritySchemes[name] = c.securityScheme("/securityDefinitions/"+pointerEscape(name), s.SecurityDefinitions[name])
+	}
+
+	for _, name := range sortedKeys(s.Paths) {
+		openAPI.Paths[name] = c.path("/paths/"+pointerEscape(name), s.Paths[name])
+	}
+
+	// Rewrite the remaining Swagger references, which all live in schemas and responses.
+	Walk(openAPI, Visitor{Enter: func(cursor *Cursor) WalkAction {
+		switch n := cursor.Value.(type) {
+		case *Schema:
+			n.Ref = c.ref(cursor.Pointer, n.Ref)
+			if n.Type.Includes("file") {
+				n.Type, n.Format = Types{"string"}, "binary"
+			}
+			if nullable, ok := n.Extensions.Bool("x-nullable"); ok {
+				n.Nullable = nullable
+				delete(n.Extensions, "x-nullable")
+			}
+			if n.Discriminator != nil {
+				n.Discriminator.propertyOnly = false
+			}
+		case *Response:
+			n.Ref = c.ref(cursor.Pointer, n.Ref)
+		}
+		return WalkContinue
+	}})
+	return openAPI, c.warnings
+}
+
+// ref rewrites a Swagger 2.0 $ref to its OpenAPI 3.0 location.
+func (c *swaggerConverter) ref(pointer, ref string) string {
+	switch {
+	case ref == "" || !strings.HasPrefix(ref, "#/"):
+		return ref
+	case strings.HasPrefix(ref, "#/definitions/"):
+		return "#/components/schemas/" + strings.TrimPrefix(ref, "#/definitions/")
+	case strings.HasPrefix(ref, "#/responses/"):
+		return "#/components/responses/" + strings.TrimPrefix(ref, "#/responses/")
+	case strings.HasPrefix(ref, "#/parameters/"):
+		return "#/components/parameters/" + strings.TrimPrefix(ref, "#/parameters/")
+	case strings.HasPrefix(ref, "#/components/"):
+		return ref
+	}
+	c.warn(pointer, "reference '%s' has no OpenAPI 3.0 equivalent", ref)
+	return ref
+}
+
+// servers builds the Server objects from host, basePath and schemes.
+func (c *swaggerConverter) servers(pointer string, schemes []string) []*Server {
+	s := c.swagger
+	if s.Host == "" {
+		if s.BasePath == "" {
+			return nil
+		}
+		return []*Server{{URL: s.BasePath}}
+	}
+	if len(schemes) == 0 {
+		c.warn(pointer+"/schemes", "no schemes are declared; assuming https")
+		schemes = []string{"https"}
+	}
+	var servers []*Server
+	for _, scheme := range schemes {
+		servers = append(servers, &Server{URL: scheme + "://" + s.Host + s.BasePath})
+	}
+	return servers
+}
+
+func (c *swaggerConverter) path(pointer string, p *SwaggerPath) *Path {
+	if p == nil {
+		return nil
+	}
+	path := &Path{Ref: p.Ref, Extensions: p.Extensions}
+	if p.Ref != "" {
+		c.warn(pointer, "path item references are kept as they are and not converted")
+	}
+	// Path level body and form parameters become part of the request body of every operation.
+	var shared []*SwaggerParameter
+	for i, param := range p.Parameters {
+		resolved := c.resolveParameter(param)
+		if resolved != nil && (resolved.In == "body" || resolved.In == "formData") {
+			shared = append(shared, param)
+			continue
+		}
+		if converted := c.parameter(fmt.Sprintf("%s/parameters/%d", pointer, i), param); converted != nil {
+			path.Parameters = append(path.Parameters, converted)
+		}
+	}
+	path.Get = c.operation(pointer+"/get", p.Get, shared)
+	path.Put = c.operation(pointer+"/put", p.Put, shared)
+	path.Post = c.operation(pointer+"/post", p.Post, shared)
+	path.Delete = c.operation(pointer+"/delete", p.Delete, shared)
+	path.Options = c.operation(pointer+"/options", p.Options, shared)
+	path.Head = c.operation(pointer+"/head", p.Head, shared)
+	path.Patch = c.operation(pointer+"/patch", p.Patch, shared)
+	return path
+}
+
+// resolveParameter returns the document level parameter a parameter refers to, or the parameter itself.
+func (c *swaggerConverter) resolveParameter(p *SwaggerParameter) *SwaggerParameter {
+	if p == nil || p.Ref == "" {
+		return p
+	}
+	return c.swagger.Parameters[strings.TrimPrefix(p.Ref, "#/parameters/")]
+}
+
+func (c *swaggerConverter) operation(pointer string, o *SwaggerOperation, shared []*SwaggerParameter) *Operation {
+	if o == nil {
+		return nil
+	}
+	s := c.swagger
+	op := &Operation{
+		Tags:         o.Tags,
+		Summary:      o.Summary,
+		Description:  o.Description,
+		ExternalDocs: o.ExternalDocs,
+		OperationID:  o.OperationID,
+		Deprecated:   o.Deprecated,
+		Security:     o.Security,
+		Responses:    map[string]*Response{},
+		Extensions:   o.Extensions,
+	}
+	if len(o.Schemes) > 0 {
+		op.Servers = c.servers(pointer, o.Schemes)
+	}
+	consumes, produces := s.Consumes, s.Produces
+	if len(o.Consumes) > 0 {
+		consumes = o.Consumes
+	}
+	if len(o.Produces) > 0 {
+		produces = o.Produces
+	}
+
+	var form []*SwaggerParameter
+	params := append(append([]*SwaggerParameter{}, shared...), o.Parameters...)
+	for i, param := range params {
+		paramPointer := fmt.Sprintf("%s/parameters/%d", pointer, i-len(shared))
+		resolved := c.resolveParameter(param)
+		if resolved == nil {
+			c.warn(paramPointer, "parameter reference '%s' cannot be resolved", param.Ref)
+			continue
+		}
+		switch resolved.In {
+		case "body":
+			if param.Ref != "" {
+				op.RequestBody = &RequestBody{Ref: "#/components/requestBodies/" + strings.TrimPrefix(param.Ref, "#/parameters/")}
+			} else {
+				op.RequestBody = c.requestBody(paramPointer, param, consumes)
+			}
+		case "formData":
+			form = append(form, resolved)
+		default:
+			if converted := c.parameter(paramPointer, param); converted != nil {
+				op.Parameters = append(op.Parameters, converted)
+			}
+		}
+	}
+	if len(form) > 0 {
+		op.RequestBody = c.formBody(pointer, form, consumes)
+	}
+
+	for _, code := range sortedKeys(o.Responses) {
+		op.Responses[code] = c.response(pointer+"/responses/"+pointerEscape(code), o.Responses[code], produces)
+	}
+	return op
+}
+
+// parameter converts a query, header or path parameter.
+func (c *swaggerConverter) parameter(pointer string, p *SwaggerParameter) *Parameter {
+	if p == nil {
+		return nil
+	}
+	if p.Ref != "" {
+		return &Parameter{Ref: c.ref(pointer, p.Ref)}
+	}
+	param := &Parameter{
+		Name:            p.Name,
+		In:              p.In,
+		Description:     p.Description,
+		Required:        p.Required,
+		AllowEmptyValue: p.AllowEmptyValue,
+		Schema:          c.itemsSchema(&p.SwaggerItems),
+		Extensions:      p.Extensions,
+	}
+	if p.Type == "array" {
+		param.Style, param.Explode, param.explodeSet = c.collectionStyle(pointer, p.In, p.CollectionFormat)
+	}
+	return param
+}
+
+// collectionStyle maps a collectionFormat to a parameter style and explode flag. set is
+// false when explode is left to the default of the style.
+func (c *swaggerConverter) collectionStyle(pointer, in, format string) (style string, explode, set bool) {
+	switch format {
+	case "", "csv":
+		if in == "query" || in == "formData" {
+			return "form", false, true
+		}
+		return "simple", false, false
+	case "multi":
+		return "form", true, true
+	case "ssv":
+		if in == "query" || in == "formData" {
+			return "spaceDelimited", false, true
+		}
+	case "pipes":
+		if in == "query" || in == "formData" {
+			return "pipeDelimited", false, true
+		}
+	}
+	c.warn(pointer+"/collectionFormat", "collectionFormat '%s' is not supported for %s parameters; using the default style", format, in)
+	return "", false, false
+}
+
+// itemsSchema converts the type and validations of a non-body parameter or header to a Schema.
+func (c *swaggerConverter) itemsSchema(i *SwaggerItems) *Schema {
+	if i == nil {
+		return nil
+	}
+	s := &Schema{
+		Format:      i.Format,
+		Default:     i.Default,
+		Maximum:     i.Maximum,
+		Minimum:     i.Minimum,
+		MaxLength:   i.MaxLength,
+		MinLength:   i.MinLength,
+		Pattern:     i.Pattern,
+		MaxItems:    i.MaxItems,
+		MinItems:    i.MinItems,
+		UniqueItems: i.UniqueItems,
+		Enum:        i.Enum,
+		MultipleOf:  i.MultipleOf,
+		Items:       c.itemsSchema(i.Items),
+	}
+	if i.Type != "" {
+		s.Type = Types{i.Type}
+	}
+	if i.Type == "file" {
+		s.Type, s.Format = Types{"string"}, "binary"
+	}
+	if i.ExclusiveMaximum {
+		s.ExclusiveMaximum = &ExclusiveBound{Exclusive: true}
+	}
+	if i.ExclusiveMinimum {
+		s.ExclusiveMinimum = &ExclusiveBound{Exclusive: true}
+	}
+	return s
+}
+
+// requestBody converts a body parameter. Its name has no equivalent in OpenAPI 3.0.
+func (c *swaggerConverter) requestBody(pointer string, p *SwaggerParameter, consumes []string) *RequestBody {
+	if p.Name != "" {
+		c.warn(pointer+"/name", "the name '%s' of a body parameter has no OpenAPI 3.0 equivalent and was dropped", p.Name)
+	}
+	rb := &RequestBody{
+		Description: p.Description,
+		Required:    p.Required,
+		Content:     map[string]*MediaType{},
+		Extensions:  p.Extensions,
+	}
+	if len(consumes) == 0 {
+		consumes = []string{"application/json"}
+	}
+	for _, mediaType := range consumes {
+		rb.Content[mediaType] = &MediaType{Schema: p.Schema}
+	}
+	return rb
+}
+
+// formBody converts formData parameters to a request body with an object schema.
+func (c *swaggerConverter) formBody(pointer string, params []*SwaggerParameter, consumes []string) *RequestBody {
+	schema := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	encoding := map[string]*Encoding{}
+	hasFile := false
+	required := false
+	for i, p := range params {
+		property := c.itemsSchema(&p.SwaggerItems)
+		property.Description = p.Description
+		schema.Properties[p.Name] = property
+		if p.Required {
+			schema.Required = append(schema.Required, p.Name)
+			required = true
+		}
+		if p.Type == "file" {
+			hasFile = true
+		}
+		if p.Type == "array" {
+			style, explode, set := c.collectionStyle(fmt.Sprintf("%s/parameters/%d", pointer, i), "formData", p.CollectionFormat)
+			if style != "" {
+				encoding[p.Name] = &Encoding{Style: style, Explode: explode, explodeSet: set}
+			}
+		}
+	}
+
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == "multipart/form-data" || mediaType == "application/x-www-form-urlencoded" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/x-www-form-urlencoded"}
+		if hasFile {
+			mediaTypes = []string{"multipart/form-data"}
+		}
+	}
+	rb := &RequestBody{Required: required, Content: map[string]*MediaType{}}
+	for _, mediaType := range mediaTypes {
+		m := &MediaType{Schema: schema}
+		if len(encoding) > 0 {
+			m.Encoding = encoding
+		}
+		rb.Content[mediaType] = m
+	}
+	return rb
+}
+
+func (c *swaggerConverter) response(pointer string, r *SwaggerResponse, produces []string) *Response {
+	if r == nil {
+		return nil
+	}
+	if r.Ref != "" {
+		return &Response{Ref: r.Ref}
+	}
+	response := &Response{Description: r.Description, Extensions: r.Extensions}
+	for _, name := range sortedKeys(r.Headers) {
+		h := r.Headers[name]
+		if response.Headers == nil {
+			response.Headers = map[string]*Header{}
+		}
+		response.Headers[name] = &Header{Description: h.Description, Schema: c.itemsSchema(&h.SwaggerItems)}
+	}
+	if r.Schema != nil {
+		if len(produces) == 0 {
+			produces = []string{"application/json"}
+		}
+		response.Content = map[string]*MediaType{}
+		for _, mediaType := range produces {
+			response.Content[mediaType] = &MediaType{Schema: r.Schema, Example: r.Examples[mediaType]}
+		}
+	}
+	for _, mediaType := range sortedKeys(r.Examples) {
+		if response.Content[mediaType] == nil {
+			c.warn(pointer+"/examples/"+pointerEscape(mediaType), "example for a MIME type the operation does not produce was dropped")
+		}
+	}
+	return response
+}
+
+func (c *swaggerConverter) securityScheme(pointer string, s *SwaggerSecurityScheme) *SecurityScheme {
+	scheme := &SecurityScheme{Description: s.Description, Extensions: s.Extensions}
+	switch s.Type {
+	case "basic":
+		scheme.Type, scheme.Scheme = "http", "basic"
+	case "apiKey":
+		scheme.Type, scheme.Name, scheme.In = "apiKey", s.Name, s.In
+	case "oauth2":
+		scheme.Type = "oauth2"
+		flow := &OAuthFlow{AuthorizationURL: s.AuthorizationURL, TokenURL: s.TokenURL, Scopes: s.Scopes}
+		if flow.Scopes == nil {
+			flow.Scopes = map[string]string{}
+		}
+		scheme.Flows = &OAuthFlows{}
+		switch s.Flow {
+		case "implicit":
+			scheme.Flows.Implicit = flow
+		case "password":
+			scheme.Flows.Password = flow
+		case "application":
+			scheme.Flows.ClientCredentials = flow
+		case "accessCode":
+			scheme.Flows.AuthorizationCode = flow
+		default:
+			c.warn(pointer+"/flow", "unknown OAuth2 flow '%s' was dropped", s.Flow)
+		}
+	default:
+		scheme.Type = s.Type
+		c.warn(pointer+"/type", "unknown security scheme type '%s'", s.Type)
+	}
+	return scheme
+}
+
+// isSwagger reports whether a JSON or YAML document declares a "swagger" version.
+func isSwagger(bytes []byte) bool {
+	var version struct {
+		Swagger string `json:"swagger" yaml:"swagger"`
+	}
+	if err := json.Unmarshal(bytes, &version); err != nil {
+		if err := yaml.Unmarshal(bytes, &version); err != nil {
+			return false
+		}
+	}
+	return version.Swagger != ""
+}
@@ -0,0 +1,126 @@
+package oas
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSwaggerToOpenAPIWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		swagger  string
+		warnings []string // The pointers of the expected warnings, in order.
+	}{
+		{
+			name:    "no warnings",
+			swagger: `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "host": "api.example.com", "schemes": ["https"], "paths": {}}`,
+		},
+		{
+			name:     "host without schemes",
+			swagger:  `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "host": "api.example.com", "paths": {}}`,
+			warnings: []string{"/schemes"},
+		},
+		{
+			name: "shared form parameter",
+			swagger: `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {},
+				"parameters": {"file": {"name": "file", "in": "formData", "type": "file"}}}`,
+			warnings: []string{"/parameters/file"},
+		},
+		{
+			name: "body parameter name",
+			swagger: `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"post": {
+				"parameters": [{"name": "pet", "in": "body", "schema": {"type": "object"}}],
+				"responses": {"204": {"description": "done"}}}}}}`,
+			warnings: []string{"/paths/~1pets/post/parameters/0/name"},
+		},
+		{
+			name: "collection format of a header",
+			swagger: `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {"/pets": {"get": {
+				"parameters": [{"name": "X-Ids", "in": "header", "type": "array", "items": {"type": "string"}, "collectionFormat": "pipes"}],
+				"responses": {"200": {"description": "ok"}}}}}}`,
+			warnings: []string{"/paths/~1pets/get/parameters/0/collectionFormat"},
+		},
+		{
+			name: "unknown reference",
+			swagger: `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {},
+				"definitions": {"Pet": {"$ref": "#/x-shared/Pet"}}}`,
+			warnings: []string{"/components/schemas/Pet"},
+		},
+		{
+			name: "unknown OAuth2 flow",
+			swagger: `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {},
+				"securityDefinitions": {"oauth": {"type": "oauth2", "flow": "device", "scopes": {}}}}`,
+			warnings: []string{"/securityDefinitions/oauth/flow"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			swagger, err := NewSwagger([]byte(test.swagger))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, warnings := swagger.ToOpenAPI()
+			if got := warningPointers(warnings); !reflect.DeepEqual(got, test.warnings) {
+				t.Errorf("warnings = %v, want pointers %v", warnings, test.warnings)
+			}
+		})
+	}
+}
+
+func TestSwaggerRoundTrip(t *testing.T) {
+	swagger, err := NewSwagger([]byte(`{
+		"swagger": "2.0",
+		"info": {"title": "Pets", "version": "1"},
+		"host": "api.example.com",
+		"basePath": "/v1",
+		"schemes": ["https"],
+		"consumes": ["application/json"],
+		"produces": ["application/json"],
+		"paths": {"/pets/{id}": {"get": {
+			"operationId": "getPet",
+			"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+			"responses": {"200": {"description": "ok", "schema": {"$ref": "#/definitions/Pet"}}}
+		}}},
+		"definitions": {"Pet": {"type": "object", "discriminator": "name", "required": ["name"], "properties": {"name": {"type": "string"}}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	openAPI, warnings := swagger.ToOpenAPI()
+	if len(warnings) > 0 {
+		t.Fatalf("ToOpenAPI() warnings = %v, want none", warnings)
+	}
+	if ref := openAPI.Paths["/pets/{id}"].Get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Pet" {
+		t.Errorf("response schema $ref = %q, want #/components/schemas/Pet", ref)
+	}
+	if data, _ := json.Marshal(openAPI.Components.Schemas["Pet"].Discriminator); string(data) != `{"propertyName":"name"}` {
+		t.Errorf("OpenAPI discriminator = %s, want {\"propertyName\":\"name\"}", data)
+	}
+
+	back, warnings := openAPI.ToSwagger()
+	if len(warnings) > 0 {
+		t.Fatalf("ToSwagger() warnings = %v, want none", warnings)
+	}
+	if back.Host != swagger.Host || back.BasePath != swagger.BasePath || !reflect.DeepEqual(back.Schemes, swagger.Schemes) {
+		t.Errorf("server = %s %s%s, want %s %s%s", back.Schemes, back.Host, back.BasePath, swagger.Schemes, swagger.Host, swagger.BasePath)
+	}
+	if ref := back.Paths["/pets/{id}"].Get.Responses["200"].Schema.Ref; ref != "#/definitions/Pet" {
+		t.Errorf("response schema $ref = %q, want #/definitions/Pet", ref)
+	}
+	if back.Definitions["Pet"] == nil {
+		t.Fatal("definition Pet was lost")
+	}
+	if data, _ := json.Marshal(back.Definitions["Pet"].Discriminator); string(data) != `"name"` {
+		t.Errorf("Swagger discriminator = %s, want \"name\"", data)
+	}
+}
+
+// warningPointers returns the pointers of warnings, or nil when there are none.
+func warningPointers(warnings []ConversionWarning) []string {
+	var pointers []string
+	for _, w := range warnings {
+		pointers = append(pointers, w.Pointer)
+	}
+	return pointers
+}
@@ -1,213 +1,655 @@
 package oas
 
 import (
-	"errors"
-	"fmt"
+	"math"
 	"reflect"
-	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
 )
 
-// Validate validates the given interface against the schema.
-func (s *Schema) Validate(i interface{}, strict bool, stopOnFailure bool) error {
-	if s == nil {
-		return errors.New("schema is nil")
+// Validate validates the given interface against the schema. The schema is compiled on
+// every call with opts, such as WithDirection or WithComponents; to validate many values,
+// compile the schema once with Compile and reuse the Validator. Failures are returned as
+// ValidationErrors.
+//
+// i may be any Go value that encoding/json can encode: structs are validated through their
+// json tags, pointers are followed and time.Time values are checked as strings.
+func (s *Schema) Validate(i interface{}, strict bool, stopOnFailure bool, opts ...CompileOption) error {
+	v, err := Compile(s, opts...)
+	if err != nil {
+		return err
 	}
+	return v.Validate(i, strict, stopOnFailure)
+}
 
+func (s *compiledSchema) validate(v *validation, pointer string, i interface{}) {
+	schema := s.schema
+	if schema.Bool != nil {
+		if !*schema.Bool {
+			v.fail(s, pointer, "schema 'false' allows no value")
+		}
+		return
+	}
+
+	i = goValue(i, schema.Format)
+	if v.strictAt(pointer) && len(s.allOf)+len(s.anyOf)+len(s.oneOf) > 0 {
+		if value := reflect.ValueOf(i); value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String {
+			s.validateCombined(v, pointer, i, value)
+			return
+		}
+	}
 	if i == nil {
-		return errors.New("input is nil")
+		if len(schema.Type) > 0 && !schema.Type.Includes("null") && !schema.Nullable {
+			v.fail(s, pointer, "input is nil")
+			return
+		}
+	} else {
+		s.validateTypes(v, pointer, reflect.ValueOf(i))
+	}
+	if v.done() {
+		return
 	}
 
-	// Get the type of the provided interface
-	value := reflect.ValueOf(i)
+	if !s.inEnum(i) {
+		v.fail(s, pointer, "%s is not one of the allowed values: %v", subjectOf(i), schema.Enum)
+	}
+	if (schema.Const != nil || schema.constNull) && !equalValues(schema.Const, i) {
+		v.fail(s, pointer, "value does not equal the constant %v", schema.Const)
+	}
+	if v.done() {
+		return
+	}
+	s.validateCombinators(v, pointer, i)
 
-	switch s.Type {
-	case "string":
-		if err := s.validateString(value); err != nil {
-			return err
+	if s.cond != nil && !v.done() {
+		if v.passes(s.cond, pointer, i) {
+			if s.then != nil {
+				s.then.validate(v, pointer, i)
+			}
+		} else if s.els != nil {
+			s.els.validate(v, pointer, i)
 		}
-	case "integer":
-		if err := s.validateInteger(value); err != nil {
-			return err
+	}
+}
+
+// validateCombined validates an object against a schema with allOf, anyOf or oneOf in strict
+// mode. The schema and its subschemas leave unknown properties to it: a property is allowed
+// when the schema, one of its allOf schemas or a matching anyOf or oneOf schema declares it.
+func (s *compiledSchema) validateCombined(v *validation, pointer string, i interface{}, value reflect.Value) {
+	restore := v.deferStrict(pointer)
+	defer restore()
+	s.validate(v, pointer, i)
+	names := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if v.done() {
+			return
 		}
-	case "number":
-		if err := s.validateNumber(value); err != nil {
-			return err
+		if !s.admits(v, pointer, name, i) {
+			v.fail(s, pointer+"/"+pointerEscape(name), "property '%s' is not allowed", name)
 		}
+	}
+}
+
+// admits reports whether the object i at pointer may have the property name in strict mode.
+func (s *compiledSchema) admits(v *validation, pointer, name string, i interface{}) bool {
+	if s == nil || s.schema.Bool != nil || s.declares(name) {
+		return true
+	}
+	for _, sub := range s.allOf {
+		if sub.admits(v, pointer, name, i) {
+			return true
+		}
+	}
+	for _, sub := range append(s.anyOf[:len(s.anyOf):len(s.anyOf)], s.oneOf...) {
+		if v.passes(sub, pointer, i) && sub.admits(v, pointer, name, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// declares reports whether the schema itself decides whether an object may have the
+// property name: through properties, patternProperties or additionalProperties.
+func (s *compiledSchema) declares(name string) bool {
+	if _, ok := s.properties[name]; ok || s.additional != nil {
+		return true
+	}
+	return s.patternSchema(name) != nil
+}
+
+// validateTypes validates a non-nil value against the type of the schema. A schema without
+// a type accepts values of any type, applying the keywords for the type of the value; with
+// several types (3.1), one has to match.
+func (s *compiledSchema) validateTypes(v *validation, pointer string, value reflect.Value) {
+	schema := s.schema
+	switch len(schema.Type) {
+	case 0:
+		if t := typeOf(value); t != "" {
+			s.validateType(v, t, pointer, value)
+		}
+	case 1:
+		s.validateType(v, schema.Type[0], pointer, value)
+	default:
+		for _, t := range schema.Type {
+			probe := v.probe()
+			if s.validateType(probe, t, pointer, value); len(probe.errs) == 0 {
+				return
+			}
+		}
+		if t := typeOf(value); schema.Type.Includes(t) {
+			s.validateType(v, t, pointer, value)
+		} else if t == "integer" && schema.Type.Includes("number") {
+			s.validateType(v, "number", pointer, value)
+		} else {
+			v.fail(s, pointer, "value does not match any of the types %s", schema.Type)
+		}
+	}
+}
+
+func (s *compiledSchema) validateType(v *validation, t string, pointer string, value reflect.Value) {
+	switch t {
+	case "string":
+		s.validateString(v, pointer, value)
+	case "integer":
+		s.validateInteger(v, pointer, value)
+	case "number":
+		s.validateNumber(v, pointer, value)
 	case "boolean":
 		if value.Kind() != reflect.Bool {
-			return fmt.Errorf("expected boolean, got %s", value.Kind().String())
+			v.fail(s, pointer, "expected boolean, got %s", value.Kind().String())
+		}
+	case "array":
+		s.validateArray(v, pointer, value)
+	case "object":
+		s.validateObject(v, pointer, value)
+	case "null":
+		v.fail(s, pointer, "expected null, got %s", value.Kind().String())
+	default:
+		v.fail(s, pointer, "unsupported schema type: %s", t)
+	}
+}
+
+// validateCombinators applies allOf, anyOf, oneOf and not.
+func (s *compiledSchema) validateCombinators(v *validation, pointer string, i interface{}) {
+	restore := v.mergeAllOf(s, pointer)
+	for _, sub := range s.allOf {
+		if sub.validate(v, pointer, i); v.done() {
+			restore()
+			return
+		}
+	}
+	restore()
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if v.passes(sub, pointer, i) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(s, pointer, "value does not match any schema of anyOf")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if v.passes(sub, pointer, i) {
+				matched++
+			}
 		}
+		switch {
+		case matched == 0:
+			v.fail(s, pointer, "value does not match any schema of oneOf")
+		case matched > 1:
+			v.fail(s, pointer, "value matches %d schemas of oneOf instead of one", matched)
+		}
+	}
+	if s.not != nil && v.passes(s.not, pointer, i) {
+		v.fail(s, pointer, "value must not match the schema of not")
+	}
+}
+
+// typeOf returns the schema type matching the kind of a Go value.
+func typeOf(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// subjectOf names a value in failure messages.
+func subjectOf(i interface{}) string {
+	switch typeOf(reflect.ValueOf(i)) {
+	case "string":
+		return "string"
+	case "integer":
+		return "integer value"
+	case "number":
+		return "number value"
+	}
+	return "value"
+}
+
+// equalValues compares two values as JSON values: numbers of different Go types are equal
+// when their values are, and arrays and objects are compared element by element.
+func equalValues(a, b interface{}) bool {
+	a, b = goValue(a, ""), goValue(b, "")
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch typeOf(va) {
 	case "array":
-		if err := s.validateArray(value, strict, stopOnFailure); err != nil {
-			return err
+		if typeOf(vb) != "array" || va.Len() != vb.Len() {
+			return false
+		}
+		for i := 0; i < va.Len(); i++ {
+			if !equalValues(va.Index(i).Interface(), vb.Index(i).Interface()) {
+				return false
+			}
 		}
+		return true
 	case "object":
-		if err := s.validateObject(value, strict, stopOnFailure); err != nil {
-			return err
+		if typeOf(vb) != "object" || va.Len() != vb.Len() || va.Type().Key().Kind() != reflect.String {
+			return false
 		}
-	default:
-		return fmt.Errorf("unsupported schema type: %s", s.Type)
+		iter := va.MapRange()
+		for iter.Next() {
+			other := mapIndex(vb, iter.Key().String())
+			if !other.IsValid() || !equalValues(iter.Value().Interface(), other.Interface()) {
+				return false
+			}
+		}
+		return true
+	case "string":
+		return typeOf(vb) == "string" && va.String() == vb.String()
+	case "boolean":
+		return typeOf(vb) == "boolean" && va.Bool() == vb.Bool()
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toFloat converts any Go number to a float64.
+func toFloat(i interface{}) (float64, bool) {
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// isMultipleOf reports whether f is a multiple of m, allowing for floating point error.
+func isMultipleOf(f, m float64) bool {
+	if m == 0 {
+		return true
 	}
+	q := f / m
+	if math.IsInf(q, 0) {
+		return false
+	}
+	return math.Abs(q-math.Round(q)) <= 1e-9*math.Max(1, math.Abs(q))
+}
 
-	return nil
+// inEnum reports whether the schema has no enum or value is one of its values.
+func (s *compiledSchema) inEnum(value interface{}) bool {
+	if s.schema.Enum == nil {
+		return true
+	}
+	for _, e := range s.schema.Enum {
+		if equalValues(e, value) {
+			return true
+		}
+	}
+	return false
 }
 
-func (s *Schema) validateString(value reflect.Value) error {
+func (s *compiledSchema) validateString(v *validation, pointer string, value reflect.Value) {
 	if value.Kind() != reflect.String {
-		return fmt.Errorf("expected string, got %s", value.Kind().String())
+		v.fail(s, pointer, "expected string, got %s", value.Kind().String())
+		return
 	}
-	if s.MinLength != nil && len(value.String()) < *s.MinLength {
-		return fmt.Errorf("string length is less than minimum length of %d", *s.MinLength)
+	schema, str := s.schema, value.String()
+	length := utf8.RuneCountInString(str)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.fail(s, pointer, "string length is less than minimum length of %d", *schema.MinLength)
 	}
-	if s.MaxLength != nil && len(value.String()) > *s.MaxLength {
-		return fmt.Errorf("string length exceeds maximum length of %d", *s.MaxLength)
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.fail(s, pointer, "string length exceeds maximum length of %d", *schema.MaxLength)
 	}
-	if s.Pattern != nil {
-		patternRegexp, err := regexp.Compile(*s.Pattern)
-		if err != nil {
-			return fmt.Errorf("error compiling pattern: %s", *s.Pattern)
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		v.fail(s, pointer, "string does not match pattern: %s", *schema.Pattern)
+	}
+	if s.format != nil {
+		if err := s.format(str); err != nil {
+			v.fail(s, pointer, "string is not a valid %s: %v", schema.Format, err)
+		}
+	}
+}
+
+func (s *compiledSchema) validateInteger(v *validation, pointer string, value reflect.Value) {
+	var n int64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			v.fail(s, pointer, "integer value %d is out of range", value.Uint())
+			return
 		}
-		if !patternRegexp.MatchString(value.String()) {
-			return fmt.Errorf("string does not match pattern: %s", *s.Pattern)
+		n = int64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		// encoding/json decodes every number to float64, so whole floats are integers.
+		f := value.Float()
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			v.fail(s, pointer, "expected integer, got %v", f)
+			return
 		}
+		n = int64(f)
+	default:
+		v.fail(s, pointer, "expected integer, got %s", value.Kind().String())
+		return
 	}
-	if s.Enum != nil {
-		found := false
-		for _, e := range s.Enum {
-			if e == value.String() {
-				found = true
-				break
+	schema, f := s.schema, float64(n)
+	if schema.Minimum != nil && compareInt(n, *schema.Minimum) < 0 {
+		v.fail(s, pointer, "integer value is less than minimum value of %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && compareInt(n, *schema.Maximum) > 0 {
+		v.fail(s, pointer, "integer value exceeds maximum value of %v", *schema.Maximum)
+	}
+	if schema.MultipleOf != nil {
+		if m := *schema.MultipleOf; m == math.Trunc(m) && m != 0 && math.Abs(m) < math.MaxInt64 {
+			if n%int64(m) != 0 {
+				v.fail(s, pointer, "integer value is not a multiple of %v", m)
 			}
-		}
-		if !found {
-			return fmt.Errorf("string is not one of the allowed values: %v", s.Enum)
+		} else if !isMultipleOf(f, m) {
+			v.fail(s, pointer, "integer value is not a multiple of %v", m)
 		}
 	}
-	if s.Format != "" {
-		// TODO: Further string format validations can be added here (e.g., email, date, etc.)
+	if schema.ExclusiveMinimum.exclusive() && schema.Minimum != nil && compareInt(n, *schema.Minimum) == 0 {
+		v.fail(s, pointer, "integer value is equal to exclusive minimum value of %v", *schema.Minimum)
+	}
+	if schema.ExclusiveMaximum.exclusive() && schema.Maximum != nil && compareInt(n, *schema.Maximum) == 0 {
+		v.fail(s, pointer, "integer value is equal to exclusive maximum value of %v", *schema.Maximum)
+	}
+	if limit, ok := schema.ExclusiveMinimum.limit(); ok && compareInt(n, limit) <= 0 {
+		v.fail(s, pointer, "integer value is not greater than exclusive minimum value of %v", limit)
+	}
+	if limit, ok := schema.ExclusiveMaximum.limit(); ok && compareInt(n, limit) >= 0 {
+		v.fail(s, pointer, "integer value is not less than exclusive maximum value of %v", limit)
 	}
-	return nil
 }
 
-func (s *Schema) validateInteger(value reflect.Value) error {
-	if value.Kind() != reflect.Int && value.Kind() != reflect.Int64 && value.Kind() != reflect.Int32 {
-		return fmt.Errorf("expected integer, got %s", value.Kind().String())
+// compareInt compares n with bound exactly, without rounding n to a float64: it returns -1
+// when n is less than bound, 0 when they are equal and 1 when n is greater.
+func compareInt(n int64, bound float64) int {
+	switch {
+	case bound >= math.MaxInt64: // 2^63, as MaxInt64 rounds up.
+		return -1
+	case bound < math.MinInt64:
+		return 1
 	}
-	if s.Minimum != nil && value.Int() < int64(*s.Minimum) {
-		return fmt.Errorf("integer value is less than minimum value of %d", s.Minimum)
+	whole := math.Trunc(bound)
+	switch m := int64(whole); {
+	case n < m:
+		return -1
+	case n > m:
+		return 1
+	case bound > whole:
+		return -1
+	case bound < whole:
+		return 1
 	}
-	if s.Maximum != nil && value.Int() > int64(*s.Maximum) {
-		return fmt.Errorf("integer value exceeds maximum value of %d", s.Maximum)
+	return 0
+}
+
+func (s *compiledSchema) validateNumber(v *validation, pointer string, value reflect.Value) {
+	f, ok := toFloat(value.Interface())
+	if !ok {
+		v.fail(s, pointer, "expected number, got %s", value.Kind().String())
+		return
 	}
-	if s.MultipleOf != nil && value.Int()%int64(*s.MultipleOf) != 0 {
-		return fmt.Errorf("integer value is not a multiple of %d", s.MultipleOf)
+	schema := s.schema
+	if schema.Minimum != nil && f < *schema.Minimum {
+		v.fail(s, pointer, "number value is less than minimum value of %f", *schema.Minimum)
 	}
-	if s.ExclusiveMinimum && s.Minimum != nil && value.Int() == int64(*s.Minimum) {
-		return fmt.Errorf("integer value is equal to exclusive minimum value of %d", s.Minimum)
+	if schema.Maximum != nil && f > *schema.Maximum {
+		v.fail(s, pointer, "number value exceeds maximum value of %f", *schema.Maximum)
 	}
-	if s.ExclusiveMaximum && s.Maximum != nil && value.Int() == int64(*s.Maximum) {
-		return fmt.Errorf("integer value is equal to exclusive maximum value of %d", s.Maximum)
+	if schema.MultipleOf != nil && !isMultipleOf(f, *schema.MultipleOf) {
+		v.fail(s, pointer, "number value is not a multiple of %f", *schema.MultipleOf)
 	}
-	if s.Enum != nil {
-		found := false
-		for _, e := range s.Enum {
-			if e == value.Int() {
-				found = true
-				break
+	if schema.ExclusiveMinimum.exclusive() && schema.Minimum != nil && f == *schema.Minimum {
+		v.fail(s, pointer, "number value is equal to exclusive minimum value of %f", *schema.Minimum)
+	}
+	if schema.ExclusiveMaximum.exclusive() && schema.Maximum != nil && f == *schema.Maximum {
+		v.fail(s, pointer, "number value is equal to exclusive maximum value of %f", *schema.Maximum)
+	}
+	if limit, ok := schema.ExclusiveMinimum.limit(); ok && f <= limit {
+		v.fail(s, pointer, "number value is not greater than exclusive minimum value of %f", limit)
+	}
+	if limit, ok := schema.ExclusiveMaximum.limit(); ok && f >= limit {
+		v.fail(s, pointer, "number value is not less than exclusive maximum value of %f", limit)
+	}
+}
+
+func (s *compiledSchema) validateArray(v *validation, pointer string, value reflect.Value) {
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		v.fail(s, pointer, "expected array, got %s", value.Kind().String())
+		return
+	}
+	// Array item validation; with prefixItems (3.1), items only applies after the prefix
+	for i := 0; i < value.Len() && !v.done(); i++ {
+		itemSchema := s.items
+		if i < len(s.prefixItems) {
+			itemSchema = s.prefixItems[i]
+		}
+		if itemSchema != nil {
+			itemSchema.validate(v, pointer+"/"+strconv.Itoa(i), value.Index(i).Interface())
+		}
+	}
+	if v.done() {
+		return
+	}
+	s.validateItemCount(v, pointer, value.Len())
+
+	if s.schema.UniqueItems {
+	unique:
+		for i := 1; i < value.Len(); i++ {
+			for j := 0; j < i; j++ {
+				if equalValues(value.Index(i).Interface(), value.Index(j).Interface()) {
+					v.fail(s, pointer, "array items %d and %d are equal", j, i)
+					break unique
+				}
 			}
 		}
-		if !found {
-			return fmt.Errorf("integer value is not one of the allowed values: %v", s.Enum)
+	}
+
+	if s.contains != nil {
+		matched := 0
+		for i := 0; i < value.Len(); i++ {
+			if v.passes(s.contains, pointer+"/"+strconv.Itoa(i), value.Index(i).Interface()) {
+				matched++
+			}
+		}
+		minContains := 1
+		if s.schema.MinContains != nil {
+			minContains = *s.schema.MinContains
+		}
+		if matched < minContains {
+			v.fail(s, pointer, "array contains %d items matching contains, less than the minimum of %d", matched, minContains)
+		}
+		if s.schema.MaxContains != nil && matched > *s.schema.MaxContains {
+			v.fail(s, pointer, "array contains %d items matching contains, more than the maximum of %d", matched, *s.schema.MaxContains)
 		}
 	}
-	return nil
 }
 
-func (s *Schema) validateNumber(value reflect.Value) error {
-	if value.Kind() != reflect.Float64 && value.Kind() != reflect.Float32 {
-		return fmt.Errorf("expected number, got %s", value.Kind().String())
-	}
-	if s.Minimum != nil && value.Float() < *s.Minimum {
-		return fmt.Errorf("number value is less than minimum value of %f", *s.Minimum)
+// validateItemCount checks minItems and maxItems against an array with n items.
+func (s *compiledSchema) validateItemCount(v *validation, pointer string, n int) {
+	schema := s.schema
+	if schema.MinItems != nil && n < *schema.MinItems {
+		v.fail(s, pointer, "array has fewer items than minimum of %d", *schema.MinItems)
 	}
-	if s.Maximum != nil && value.Float() > *s.Maximum {
-		return fmt.Errorf("number value exceeds maximum value of %f", *s.Maximum)
+	if schema.MaxItems != nil && n > *schema.MaxItems {
+		v.fail(s, pointer, "array has more items than maximum of %d", *schema.MaxItems)
 	}
-	if s.MultipleOf != nil && float64(int(value.Float()/(*s.MultipleOf))) != value.Float()/(*s.MultipleOf) {
-		return fmt.Errorf("number value is not a multiple of %f", *s.MultipleOf)
+}
+
+func (s *compiledSchema) validateObject(v *validation, pointer string, value reflect.Value) {
+	if value.Kind() != reflect.Map {
+		v.fail(s, pointer, "expected object, got %s", value.Kind().String())
+		return
 	}
-	if s.ExclusiveMinimum && s.Minimum != nil && value.Float() == *s.Minimum {
-		return fmt.Errorf("number value is equal to exclusive minimum value of %f", *s.Minimum)
+	if value.Type().Key().Kind() != reflect.String {
+		v.fail(s, pointer, "expected object, got map with %s keys", value.Type().Key().Kind().String())
+		return
 	}
-	if s.ExclusiveMaximum && s.Maximum != nil && value.Float() == *s.Maximum {
-		return fmt.Errorf("number value is equal to exclusive maximum value of %f", *s.Maximum)
+
+	names := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		names = append(names, key.String())
 	}
-	if s.Enum != nil {
-		found := false
-		for _, e := range s.Enum {
-			if e == value.Float() {
-				found = true
-				break
-			}
+	sort.Strings(names)
+	for _, name := range names {
+		propPointer := pointer + "/" + pointerEscape(name)
+		if s.names != nil {
+			s.names.validate(v, propPointer, name)
 		}
-		if !found {
-			return fmt.Errorf("number value is not one of the allowed values: %v", s.Enum)
+		propValue := mapIndex(value, name).Interface()
+		for _, sub := range s.propertySchemas(v, pointer, name) {
+			if sub.validate(v, propPointer, propValue); v.done() {
+				return
+			}
 		}
 	}
-	return nil
-}
 
-func (s *Schema) validateArray(value reflect.Value, strict, stopOnFailure bool) error {
-	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
-		return fmt.Errorf("expected array, got %s", value.Kind().String())
-	}
-	// Array item validation
-	for i := 0; i < value.Len(); i++ {
-		item := value.Index(i).Interface()
-		if s.Items != nil {
-			if err := s.Items.Validate(item, strict, stopOnFailure); err != nil {
-				return fmt.Errorf("array item at index %d failed validation: %v", i, err)
+	present := func(name string) bool {
+		return mapIndex(value, name).IsValid()
+	}
+	s.validatePresence(v, pointer, present)
+	if v.done() {
+		return
+	}
+
+	for _, propName := range s.dependentNames {
+		if present(propName) {
+			s.dependentSchemas[propName].validate(v, pointer, value.Interface())
+			if v.done() {
+				return
 			}
 		}
 	}
-	return nil
+	s.validatePropertyCount(v, pointer, value.Len())
 }
 
-func (s *Schema) validateObject(value reflect.Value, strict, stopOnFailure bool) error {
-	if value.Kind() != reflect.Map {
-		return fmt.Errorf("expected object, got %s", value.Kind().String())
+// propertySchemas returns the schemas the property name of an object is validated against:
+// its schema in properties and those of the matching patternProperties or, when there are
+// none, additionalProperties. It fails when the property is not allowed.
+func (s *compiledSchema) propertySchemas(v *validation, pointer, name string) []*compiledSchema {
+	var schemas []*compiledSchema
+	declared := false
+	if property, ok := s.properties[name]; ok {
+		declared = true
+		if s.property(v, pointer, name) && property != nil {
+			schemas = append(schemas, property)
+		}
+	}
+	for _, p := range s.patterns {
+		if p.regexp.MatchString(name) {
+			declared = true
+			schemas = append(schemas, p.schema)
+		}
+	}
+	if declared {
+		return schemas
 	}
+	switch {
+	case s.additional != nil && s.additional.schema.Bool != nil && !*s.additional.schema.Bool:
+		v.fail(s, pointer+"/"+pointerEscape(name), "property '%s' is not allowed", name)
+	case s.additional != nil:
+		schemas = append(schemas, s.additional)
+	case v.strictAt(pointer):
+		v.fail(s, pointer+"/"+pointerEscape(name), "property '%s' is not allowed", name)
+	}
+	return schemas
+}
 
-	requiredMap := make(map[string]bool, len(s.Required))
-	for _, r := range s.Required {
-		requiredMap[r] = false
+// property reports whether the present property name may be validated, failing when it is
+// declared readOnly or writeOnly for the direction being validated.
+func (s *compiledSchema) property(v *validation, pointer, name string) bool {
+	if property := s.properties[name]; property != nil && v.excluded(property) {
+		if v.direction == RequestDirection {
+			v.fail(property, pointer+"/"+pointerEscape(name), "property '%s' is read-only and not allowed in a %s", name, v.direction)
+		} else {
+			v.fail(property, pointer+"/"+pointerEscape(name), "property '%s' is write-only and not allowed in a %s", name, v.direction)
+		}
+		return false
 	}
+	return true
+}
 
-	for propName, propSchema := range s.Properties {
-		propValue := value.MapIndex(reflect.ValueOf(propName))
-		if propValue.IsValid() {
-			if err := propSchema.Validate(propValue.Interface(), strict, stopOnFailure); err != nil {
-				return fmt.Errorf("property '%s' failed validation: %v", propName, err)
-			}
+// validatePresence checks the keywords of an object schema that depend only on which
+// properties are present.
+func (s *compiledSchema) validatePresence(v *validation, pointer string, present func(name string) bool) {
+	schema := s.schema
+	for _, propName := range schema.Required {
+		if v.excludedProperty(s, pointer, propName) {
+			continue
 		}
-		if _, ok := requiredMap[propName]; ok {
-			requiredMap[propName] = true
-		} else if strict {
-			return fmt.Errorf("property '%s' is not allowed", propName)
+		if !present(propName) {
+			v.fail(s, pointer, "required property '%s' is missing", propName)
+			if v.done() {
+				return
+			}
 		}
 	}
 
-	for propName, required := range requiredMap {
-		if !required {
-			return fmt.Errorf("required property '%s' is missing", propName)
+	for _, propName := range sortedKeys(schema.DependentRequired) {
+		if !present(propName) {
+			continue
+		}
+		for _, dependency := range schema.DependentRequired[propName] {
+			if !present(dependency) {
+				v.fail(s, pointer, "property '%s' is required when '%s' is present", dependency, propName)
+			}
 		}
 	}
+}
 
-	if s.MinProperties != nil && value.Len() < *s.MinProperties {
-		return fmt.Errorf("object has fewer properties than minimum of %d", *s.MinProperties)
+// validatePropertyCount checks minProperties and maxProperties against an object with n properties.
+func (s *compiledSchema) validatePropertyCount(v *validation, pointer string, n int) {
+	schema := s.schema
+	if schema.MinProperties != nil && n < *schema.MinProperties {
+		v.fail(s, pointer, "object has fewer properties than minimum of %d", *schema.MinProperties)
 	}
-	if s.MaxProperties != nil && value.Len() > *s.MaxProperties {
-		return fmt.Errorf("object has more properties than maximum of %d", *s.MaxProperties)
+	if schema.MaxProperties != nil && n > *schema.MaxProperties {
+		v.fail(s, pointer, "object has more properties than maximum of %d", *schema.MaxProperties)
 	}
-	return nil
 }
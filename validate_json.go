@@ -0,0 +1,348 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// ValidateJSON validates the JSON value in data against the schema without decoding it
+// first. The schema is compiled on every call, as Validate describes. See Validator.ValidateJSON.
+func (s *Schema) ValidateJSON(data []byte, strict bool, stopOnFailure bool, opts ...CompileOption) error {
+	v, err := Compile(s, opts...)
+	if err != nil {
+		return err
+	}
+	return v.ValidateJSON(data, strict, stopOnFailure)
+}
+
+// ValidateJSON validates the JSON value in data against the schema in a single pass over its
+// tokens. Failures are returned as ValidationErrors carrying the byte offset of the failing
+// value; malformed JSON is returned as a *ParseError.
+func (v *Validator) ValidateJSON(data []byte, strict bool, stopOnFailure bool) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := v.ValidateDecoder(dec, strict, stopOnFailure); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &ParseError{Err: fmt.Errorf("unexpected data after the value at offset %d", dec.InputOffset())}
+	}
+	return nil
+}
+
+// ValidateDecoder reads the next JSON value from dec and validates it against the schema.
+// Objects and arrays are streamed, except where a keyword such as if or dependentSchemas
+// needs the whole value; such values are decoded and their failures carry the offset at
+// which they start. Numbers keep their precision: integers are compared as int64 unless
+// they do not fit. ValidateDecoder calls dec.UseNumber.
+func (v *Validator) ValidateDecoder(dec *json.Decoder, strict bool, stopOnFailure bool) error {
+	dec.UseNumber()
+	stream := &jsonStream{dec: dec, validation: &validation{strict: strict, stopOnFailure: stopOnFailure, direction: v.direction}}
+	if err := stream.value(v.root, ""); err != nil {
+		return jsonStreamError(err, dec)
+	}
+	if len(stream.errs) == 0 {
+		return nil
+	}
+	return stream.errs
+}
+
+// jsonStream validates the values read from a json.Decoder.
+type jsonStream struct {
+	*validation
+	dec *json.Decoder
+}
+
+// value validates the next value of the stream against s.
+func (j *jsonStream) value(s *compiledSchema, pointer string) error {
+	return j.values([]*compiledSchema{s}, pointer)
+}
+
+// values validates the next value of the stream against every schema of roots. Objects and
+// arrays are streamed against the roots and the branches of their allOf at once.
+func (j *jsonStream) values(roots []*compiledSchema, pointer string) error {
+	j.offset = j.nextOffset()
+	if j.done() {
+		return j.skip()
+	}
+	var schemas []*compiledSchema
+	var groups []int // The index in roots of the root each schema was streamed from.
+	for r, s := range roots {
+		var ok bool
+		if schemas, ok = streamSchemas(s, schemas); !ok {
+			return j.decode(roots, pointer)
+		}
+		for len(groups) < len(schemas) {
+			groups = append(groups, r)
+		}
+	}
+	if len(schemas) == 0 {
+		return j.skip()
+	}
+	token, err := j.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		return j.object(schemas, groups, pointer)
+	case json.Delim('['):
+		return j.array(schemas, pointer)
+	}
+	for _, s := range roots {
+		if s != nil && !j.done() {
+			s.validate(j.validation, pointer, scalarValue(s, token))
+		}
+	}
+	return nil
+}
+
+// streamSchemas appends s and, recursively, the branches of its allOf to schemas. It returns
+// false when one of them needs the whole value.
+func streamSchemas(s *compiledSchema, schemas []*compiledSchema) ([]*compiledSchema, bool) {
+	if s == nil {
+		return schemas, true
+	}
+	if s.buffered() {
+		return nil, false
+	}
+	schemas = append(schemas, s)
+	for _, sub := range s.allOf {
+		var ok bool
+		if schemas, ok = streamSchemas(sub, schemas); !ok {
+			return nil, false
+		}
+	}
+	return schemas, true
+}
+
+// buffered reports whether validating a value against s needs the whole value.
+func (s *compiledSchema) buffered() bool {
+	schema := s.schema
+	return schema.Bool != nil || len(schema.Type) > 1 || schema.Const != nil || schema.Enum != nil ||
+		s.cond != nil || len(s.anyOf) > 0 || len(s.oneOf) > 0 || s.not != nil ||
+		schema.UniqueItems || s.contains != nil || len(s.patterns) > 0 || len(s.dependentSchemas) > 0
+}
+
+// decode reads the next value of the stream and validates it in memory against every
+// schema of roots. Numbers are kept as json.Number so that they keep their precision.
+func (j *jsonStream) decode(roots []*compiledSchema, pointer string) error {
+	var raw json.RawMessage
+	if err := j.dec.Decode(&raw); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	for _, s := range roots {
+		if s != nil && !j.done() {
+			s.validate(j.validation, pointer, value)
+		}
+	}
+	return nil
+}
+
+// object validates the members of an object whose opening brace has been read against
+// schemas. In strict mode, a member is allowed when a schema of the same group, a root
+// or one of its allOf schemas, declares it.
+func (j *jsonStream) object(schemas []*compiledSchema, groups []int, pointer string) error {
+	start := j.offset
+	// A schema without a type applies its object keywords to every object.
+	var objects []*compiledSchema
+	var objectGroups []int
+	for k, s := range schemas {
+		if len(s.schema.Type) == 0 || s.schema.Type[0] == "object" {
+			objects = append(objects, s)
+			objectGroups = append(objectGroups, groups[k])
+		} else {
+			s.validateType(j.validation, s.schema.Type[0], pointer, mapValue)
+		}
+	}
+
+	present := map[string]bool{}
+	for j.dec.More() {
+		token, err := j.dec.Token()
+		if err != nil {
+			return err
+		}
+		name := token.(string)
+		present[name] = true
+		// Without patternProperties, a property has at most one schema per object schema.
+		var properties []*compiledSchema
+		if !j.done() {
+			j.offset = j.nextOffset()
+			restore := j.deferStrict(pointer)
+			for _, s := range objects {
+				if s.names != nil {
+					s.names.validate(j.validation, pointer+"/"+pointerEscape(name), name)
+				}
+				if sub := s.propertySchemas(j.validation, pointer, name); len(sub) > 0 {
+					properties = append(properties, sub[0])
+				}
+			}
+			restore()
+			if j.strictAt(pointer) {
+				j.unknown(objects, objectGroups, pointer, name)
+			}
+		}
+		if err := j.values(properties, pointer+"/"+pointerEscape(name)); err != nil {
+			return err
+		}
+	}
+	if _, err := j.dec.Token(); err != nil {
+		return err
+	}
+
+	j.offset = start
+	for k, s := range objects {
+		if j.done() {
+			break
+		}
+		// The schemas of a group are a root and its allOf schemas, which share their properties.
+		restore := j.mergeAllOf(schemas[slices.Index(groups, objectGroups[k])], pointer)
+		s.validatePresence(j.validation, pointer, func(name string) bool { return present[name] })
+		restore()
+		s.validatePropertyCount(j.validation, pointer, len(present))
+	}
+	return nil
+}
+
+// unknown fails once for every group of schemas none of which declares the member name.
+func (j *jsonStream) unknown(schemas []*compiledSchema, groups []int, pointer, name string) {
+	for k := 0; k < len(schemas); {
+		first, declared := k, false
+		for ; k < len(schemas) && groups[k] == groups[first]; k++ {
+			declared = declared || schemas[k].declares(name)
+		}
+		if !declared {
+			j.fail(schemas[first], pointer+"/"+pointerEscape(name), "property '%s' is not allowed", name)
+		}
+	}
+}
+
+// array validates the items of an array whose opening bracket has been read against schemas.
+func (j *jsonStream) array(schemas []*compiledSchema, pointer string) error {
+	// A schema without a type applies its array keywords to every array.
+	var arrays []*compiledSchema
+	for _, s := range schemas {
+		if len(s.schema.Type) == 0 || s.schema.Type[0] == "array" {
+			arrays = append(arrays, s)
+		} else {
+			s.validateType(j.validation, s.schema.Type[0], pointer, sliceValue)
+		}
+	}
+
+	start := j.offset
+	n := 0
+	for ; j.dec.More(); n++ {
+		var items []*compiledSchema
+		for _, s := range arrays {
+			item := s.items
+			if n < len(s.prefixItems) {
+				item = s.prefixItems[n]
+			}
+			items = append(items, item)
+		}
+		if err := j.values(items, pointer+"/"+strconv.Itoa(n)); err != nil {
+			return err
+		}
+	}
+	if _, err := j.dec.Token(); err != nil {
+		return err
+	}
+
+	j.offset = start
+	for _, s := range arrays {
+		if j.done() {
+			break
+		}
+		s.validateItemCount(j.validation, pointer, n)
+	}
+	return nil
+}
+
+// skip reads the next value of the stream without validating it.
+func (j *jsonStream) skip() error {
+	depth := 0
+	for {
+		token, err := j.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// nextOffset returns the offset at which the next value of the stream starts, skipping the
+// whitespace and separators the decoder has not consumed yet.
+func (j *jsonStream) nextOffset() int64 {
+	offset := j.dec.InputOffset()
+	buffered := j.dec.Buffered()
+	if r, ok := buffered.(*bytes.Reader); ok && r.Len() == 0 {
+		// The buffer is empty before the first value is read; More fills it.
+		j.dec.More()
+		buffered = j.dec.Buffered()
+	}
+	var b [1]byte
+	for {
+		if _, err := buffered.Read(b[:]); err != nil {
+			return offset
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+}
+
+// mapValue and sliceValue stand in for streamed objects and arrays in type failures.
+var (
+	mapValue   = reflect.ValueOf(map[string]interface{}{})
+	sliceValue = reflect.ValueOf([]interface{}{})
+)
+
+// scalarValue converts a JSON token to the Go value validated against s. Numbers become
+// int64 when s accepts integers and the number is one, and float64 otherwise.
+func scalarValue(s *compiledSchema, token json.Token) interface{} {
+	number, ok := token.(json.Number)
+	if !ok {
+		return token
+	}
+	if !s.schema.Type.Includes("number") || s.schema.Type.Includes("integer") {
+		if n, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+			return n
+		}
+	}
+	f, _ := strconv.ParseFloat(string(number), 64)
+	return f
+}
+
+// jsonStreamError converts a decoding error to a ParseError located at the decoder's offset.
+func jsonStreamError(err error, dec *json.Decoder) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &ParseError{Err: fmt.Errorf("%w at offset %d", err, syntaxErr.Offset)}
+	}
+	return &ParseError{Err: fmt.Errorf("%w at offset %d", err, dec.InputOffset())}
+}
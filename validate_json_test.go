@@ -0,0 +1,80 @@
+package oas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateJSON(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "integer", "maximum": 9007199254740992},
+			"price": {"type": "number", "multipleOf": 0.01},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 3}},
+			"either": {"anyOf": [{"type": "string"}, {"type": "object", "required": ["a"]}]}
+		}
+	}`
+	tests := []struct {
+		name          string
+		data          string
+		strict        bool
+		stopOnFailure bool
+		want          []string // The failures, as "field@offset".
+		parseErr      string   // A substring of the expected *ParseError.
+	}{
+		{name: "valid", data: `{"id": 9007199254740992, "price": 1.25, "tags": ["a"], "either": {"a": 1}}`},
+		{name: "integer beyond float64 precision", data: `{"id": 9007199254740993}`, want: []string{"/id@7"}},
+		{name: "integral number", data: `{"id": 2.0}`},
+		{name: "fractional integer", data: `{"id": 2.5}`, want: []string{"/id@7"}},
+		{name: "offsets of nested values", data: `{"id": 1, "tags": ["a", "long", 3]}`, want: []string{"/tags/1@24", "/tags/2@32"}},
+		{name: "offset beyond the decoder buffer", data: `{"id": 1, "tags": [` + strings.Repeat(`"a", `, 1000) + `"long"]}`, want: []string{"/tags/1000@5019"}},
+		{name: "buffered value located at its start", data: `{"id": 1, "either": {"b": 1}}`, want: []string{"/either@20"}},
+		{name: "missing property located at the object", data: `  {"tags": []}`, want: []string{"@2"}},
+		{name: "stop on failure", data: `{"tags": ["long", "long"]}`, stopOnFailure: true, want: []string{"/tags/0@10"}},
+		{name: "unknown property in strict mode", data: `{"id": 1, "name": "a"}`, strict: true, want: []string{"/name@18"}},
+		{name: "unknown property outside strict mode", data: `{"id": 1, "name": "a"}`},
+		{name: "malformed JSON", data: `{"id": }`, parseErr: "at offset 8"},
+		{name: "truncated JSON", data: `{"id": 1`, parseErr: "unexpected end of JSON input"},
+		{name: "trailing data", data: `{"id": 1} {}`, parseErr: "unexpected data after the value"},
+	}
+	var s Schema
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		t.Fatal(err)
+	}
+	v, err := Compile(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := v.ValidateJSON([]byte(test.data), test.strict, test.stopOnFailure)
+			if test.parseErr != "" {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) || !strings.Contains(err.Error(), test.parseErr) {
+					t.Errorf("ValidateJSON() = %v, want a *ParseError containing %q", err, test.parseErr)
+				}
+				return
+			}
+			var got []string
+			var errs ValidationErrors
+			if errors.As(err, &errs) {
+				for _, e := range errs {
+					if !e.HasOffset {
+						t.Errorf("%v has no offset", e)
+					}
+					got = append(got, fmt.Sprintf("%s@%d", e.Field, e.Offset))
+				}
+			} else if err != nil {
+				t.Fatalf("ValidateJSON() = %v, want ValidationErrors", err)
+			}
+			if strings.Join(got, " ") != strings.Join(test.want, " ") {
+				t.Errorf("ValidateJSON() = %v, want failures %v (%v)", got, test.want, err)
+			}
+		})
+	}
+}
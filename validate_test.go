@@ -0,0 +1,151 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// suiteFiles are the files of the JSON Schema Test Suite for the keywords the Validator doc
+// comment lists, relative to tests/draft2020-12.
+var suiteFiles = []string{
+	"type.json",
+	"enum.json",
+	"const.json",
+	"multipleOf.json",
+	"maximum.json",
+	"exclusiveMaximum.json",
+	"minimum.json",
+	"exclusiveMinimum.json",
+	"maxLength.json",
+	"minLength.json",
+	"pattern.json",
+	"optional/format/date-time.json",
+	"optional/format/date.json",
+	"optional/format/time.json",
+	"optional/format/email.json",
+	"optional/format/uuid.json",
+	"optional/format/ipv4.json",
+	"optional/format/ipv6.json",
+	"optional/format/uri.json",
+	"optional/format/hostname.json",
+	"items.json",
+	"prefixItems.json",
+	"maxItems.json",
+	"minItems.json",
+	"uniqueItems.json",
+	"contains.json",
+	"maxContains.json",
+	"minContains.json",
+	"properties.json",
+	"patternProperties.json",
+	"additionalProperties.json",
+	"propertyNames.json",
+	"required.json",
+	"dependentRequired.json",
+	"dependentSchemas.json",
+	"maxProperties.json",
+	"minProperties.json",
+	"allOf.json",
+	"anyOf.json",
+	"oneOf.json",
+	"not.json",
+	"if-then-else.json",
+	"boolean_schema.json",
+	"ref.json",
+	"defs.json",
+	"unevaluatedItems.json",
+	"unevaluatedProperties.json",
+}
+
+// suiteSkipped are the files, and the groups named "<file>/<description>", that test what the
+// Validator does not support, with the reason.
+var suiteSkipped = map[string]string{
+	"unevaluatedItems.json":      "unevaluatedItems is not enforced",
+	"unevaluatedProperties.json": "unevaluatedProperties is not enforced",
+	"defs.json":                  "remote references, such as the one to the meta-schema, are not resolved",
+	"ref.json/ref applies alongside sibling keywords": "the siblings of $ref are ignored, as in OpenAPI 3.0",
+	"ref.json/remote ref, containing refs itself":     "remote references are not resolved",
+}
+
+type suiteGroup struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string          `json:"description"`
+		Data        json.RawMessage `json:"data"`
+		Valid       bool            `json:"valid"`
+	} `json:"tests"`
+}
+
+func TestJSONSchemaTestSuite(t *testing.T) {
+	for _, file := range suiteFiles {
+		t.Run(file, func(t *testing.T) {
+			if reason, ok := suiteSkipped[file]; ok {
+				t.Skip(reason)
+			}
+			data, err := os.ReadFile(filepath.Join("testdata", "JSON-Schema-Test-Suite", "tests", "draft2020-12", filepath.FromSlash(file)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var groups []suiteGroup
+			if err := json.Unmarshal(data, &groups); err != nil {
+				t.Fatal(err)
+			}
+			for _, group := range groups {
+				t.Run(group.Description, func(t *testing.T) {
+					if reason, ok := suiteSkipped[file+"/"+group.Description]; ok {
+						t.Skip(reason)
+					}
+					s := &Schema{}
+					if err := json.Unmarshal(group.Schema, s); err != nil {
+						t.Fatalf("error decoding schema: %v", err)
+					}
+					v, err := Compile(s)
+					if err != nil {
+						t.Fatalf("error compiling schema: %v", err)
+					}
+					for _, test := range group.Tests {
+						dec := json.NewDecoder(bytes.NewReader(test.Data))
+						dec.UseNumber()
+						var value interface{}
+						if err := dec.Decode(&value); err != nil {
+							t.Fatalf("%s: error decoding data: %v", test.Description, err)
+						}
+						if err := v.Validate(value, false, false); (err == nil) != test.Valid {
+							t.Errorf("%s: Validate(%s) = %v, want valid %t", test.Description, test.Data, err, test.Valid)
+						}
+						if err := v.ValidateJSON(test.Data, false, false); (err == nil) != test.Valid {
+							t.Errorf("%s: ValidateJSON(%s) = %v, want valid %t", test.Description, test.Data, err, test.Valid)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestCompareInt(t *testing.T) {
+	tests := []struct {
+		n     int64
+		bound float64
+		want  int
+	}{
+		{n: 3, bound: 3, want: 0},
+		{n: 3, bound: 3.5, want: -1},
+		{n: 4, bound: 3.5, want: 1},
+		{n: -3, bound: -3.5, want: 1},
+		{n: 9007199254740993, bound: 9007199254740992, want: 1},
+		{n: math.MaxInt64, bound: math.MaxInt64, want: -1},
+		{n: math.MinInt64, bound: math.MinInt64, want: 0},
+		{n: math.MinInt64, bound: -1e300, want: 1},
+	}
+	for _, test := range tests {
+		if got := compareInt(test.n, test.bound); got != test.want {
+			t.Errorf("compareInt(%d, %v) = %d, want %d", test.n, test.bound, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,495 @@
+package oas
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validator validates values against a compiled schema. References are resolved, patterns
+// and formats are compiled once, and a Validator is safe for concurrent use.
+//
+// The keywords enforced are type, including the list form of 3.1 and nullable, enum, const,
+// multipleOf, maximum, exclusiveMaximum, minimum, exclusiveMinimum, maxLength and minLength,
+// which count code points, pattern, format, items, prefixItems, maxItems, minItems,
+// uniqueItems, contains, maxContains, minContains, properties, patternProperties,
+// additionalProperties, propertyNames, required, dependentRequired, dependentSchemas,
+// maxProperties, minProperties, allOf, anyOf, oneOf, not, if, then and else, as well as
+// readOnly and writeOnly with WithDirection and $ref to component schemas or, for any other
+// fragment such as "#/$defs/Tag", to a schema within the one compiled. The formats
+// checked are date-time, date, time, email, uuid, ipv4, ipv6, uri, hostname and those
+// registered with WithFormat. unevaluatedItems, unevaluatedProperties and contentSchema are
+// not enforced.
+type Validator struct {
+	root      *compiledSchema
+	direction Direction
+}
+
+// Direction is the direction of the message a value is sent in, which decides whether
+// readOnly or writeOnly properties may appear in it.
+type Direction int
+
+const (
+	// AnyDirection ignores readOnly and writeOnly.
+	AnyDirection Direction = iota
+	// RequestDirection rejects readOnly properties and does not require them.
+	RequestDirection
+	// ResponseDirection rejects writeOnly properties, such as passwords, and does not require them.
+	ResponseDirection
+)
+
+func (d Direction) String() string {
+	switch d {
+	case RequestDirection:
+		return "request"
+	case ResponseDirection:
+		return "response"
+	}
+	return "any"
+}
+
+// CompileOption configures how a schema is compiled.
+type CompileOption func(*compiler)
+
+// WithComponents resolves "#/components/..." references against c, including those into
+// the $defs of a component schema.
+func WithComponents(c *Components) CompileOption {
+	return func(cc *compiler) {
+		cc.components = c
+	}
+}
+
+// WithFormat registers a checker for the string format name, replacing the built-in one if any.
+func WithFormat(name string, check func(string) error) CompileOption {
+	return func(cc *compiler) {
+		cc.formats[name] = check
+	}
+}
+
+// WithDirection validates values as sent in the given direction.
+func WithDirection(d Direction) CompileOption {
+	return func(cc *compiler) {
+		cc.direction = d
+	}
+}
+
+// Compile builds a Validator for s.
+func Compile(s *Schema, opts ...CompileOption) (*Validator, error) {
+	if s == nil {
+		return nil, errors.New("schema is nil")
+	}
+	c := &compiler{compiled: map[*Schema]*compiledSchema{}, formats: map[string]func(string) error{}}
+	for name, check := range formatCheckers {
+		c.formats[name] = check
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.root = s
+	root, err := c.compile(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{root: root, direction: c.direction}, nil
+}
+
+// Validate validates value against the schema. With strict, object properties the schema
+// does not allow are rejected. With stopOnFailure, validation stops at the first failure;
+// otherwise every failure is reported. The returned error is a ValidationErrors whose Field
+// is the JSON Pointer of the failing value. value is interpreted as Schema.Validate describes.
+func (v *Validator) Validate(value interface{}, strict bool, stopOnFailure bool) error {
+	state := &validation{strict: strict, stopOnFailure: stopOnFailure, direction: v.direction, offset: -1}
+	v.root.validate(state, "", value)
+	if len(state.errs) == 0 {
+		return nil
+	}
+	return state.errs
+}
+
+// compiledSchema is a schema with its references resolved and its patterns compiled.
+type compiledSchema struct {
+	schema   *Schema
+	position Position
+	pattern  *regexp.Regexp
+	format   func(string) error
+
+	then, els, cond  *compiledSchema
+	allOf            []*compiledSchema
+	anyOf            []*compiledSchema
+	oneOf            []*compiledSchema
+	not              *compiledSchema
+	items            *compiledSchema
+	prefixItems      []*compiledSchema
+	contains         *compiledSchema
+	properties       map[string]*compiledSchema
+	propertyNames    []string // The keys of properties, sorted for stable error order.
+	patterns         []compiledPattern
+	additional       *compiledSchema
+	names            *compiledSchema // The schema of propertyNames.
+	dependentSchemas map[string]*compiledSchema
+	dependentNames   []string // The keys of dependentSchemas, sorted.
+}
+
+// compiledPattern is an entry of patternProperties.
+type compiledPattern struct {
+	regexp *regexp.Regexp
+	schema *compiledSchema
+}
+
+type compiler struct {
+	components *Components
+	root       *Schema // The schema passed to Compile, which other local references resolve against.
+	formats    map[string]func(string) error
+	direction  Direction
+	compiled   map[*Schema]*compiledSchema
+}
+
+func (c *compiler) compile(s *Schema) (*compiledSchema, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if cs, ok := c.compiled[s]; ok {
+		return cs, nil
+	}
+	if s.Ref != "" {
+		target, err := c.resolve(s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if s.ReadOnly || s.WriteOnly {
+			// readOnly and writeOnly beside a $ref still apply to the property it defines.
+			cs := &compiledSchema{schema: &Schema{ReadOnly: s.ReadOnly, WriteOnly: s.WriteOnly}, position: s.position}
+			c.compiled[s] = cs
+			sub, err := c.compile(target)
+			cs.allOf = []*compiledSchema{sub}
+			return cs, err
+		}
+		cs, err := c.compile(target)
+		c.compiled[s] = cs
+		return cs, err
+	}
+
+	cs := &compiledSchema{schema: s, position: s.position}
+	c.compiled[s] = cs
+	if s.Pattern != nil {
+		pattern, err := regexp.Compile(*s.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling pattern: %s", *s.Pattern)
+		}
+		cs.pattern = pattern
+	}
+	cs.format = c.formats[s.Format]
+
+	var err error
+	compile := func(s *Schema) *compiledSchema {
+		compiled, e := c.compile(s)
+		if e != nil && err == nil {
+			err = e
+		}
+		return compiled
+	}
+	compileAll := func(schemas []*Schema) []*compiledSchema {
+		var compiled []*compiledSchema
+		for _, s := range schemas {
+			if s != nil {
+				compiled = append(compiled, compile(s))
+			}
+		}
+		return compiled
+	}
+	cs.cond, cs.then, cs.els = compile(s.If), compile(s.Then), compile(s.Else)
+	cs.allOf, cs.anyOf, cs.oneOf = compileAll(s.AllOf), compileAll(s.AnyOf), compileAll(s.OneOf)
+	cs.not = compile(s.Not)
+	cs.items = compile(s.Items)
+	cs.prefixItems = compileAll(s.PrefixItems)
+	cs.contains = compile(s.Contains)
+	cs.additional = compile(s.AdditionalProperties)
+	cs.names = compile(s.PropertyNames)
+	for _, expr := range sortedKeys(s.PatternProperties) {
+		pattern, e := regexp.Compile(expr)
+		if e != nil {
+			return nil, fmt.Errorf("error compiling pattern property: %s", expr)
+		}
+		if s.PatternProperties[expr] != nil {
+			cs.patterns = append(cs.patterns, compiledPattern{regexp: pattern, schema: compile(s.PatternProperties[expr])})
+		}
+	}
+	if len(s.Properties) > 0 {
+		cs.properties = make(map[string]*compiledSchema, len(s.Properties))
+		for name, property := range s.Properties {
+			cs.properties[name] = compile(property)
+			cs.propertyNames = append(cs.propertyNames, name)
+		}
+		sort.Strings(cs.propertyNames)
+	}
+	if len(s.DependentSchemas) > 0 {
+		cs.dependentSchemas = make(map[string]*compiledSchema, len(s.DependentSchemas))
+		for name, dependent := range s.DependentSchemas {
+			cs.dependentSchemas[name] = compile(dependent)
+			cs.dependentNames = append(cs.dependentNames, name)
+		}
+		sort.Strings(cs.dependentNames)
+	}
+	return cs, err
+}
+
+// resolve returns the schema a local reference points to: a "#/components/..." reference
+// such as "#/components/schemas/Pet" or "#/components/schemas/Pet/$defs/Tag" within the
+// components, and any other fragment, such as "#" or "#/$defs/Tag", within the root schema.
+func (c *compiler) resolve(ref string) (*Schema, error) {
+	fragment, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("reference '%s' is not a local schema reference", ref)
+	}
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("reference '%s' is not a valid fragment", ref)
+	}
+	var target interface{}
+	if components, ok := strings.CutPrefix(pointer, "/components/"); ok {
+		if c.components != nil {
+			target = lookupPointer(c.components, "/"+components)
+		}
+	} else if pointer == "" {
+		target = c.root
+	} else if strings.HasPrefix(pointer, "/") {
+		target = lookupPointer(c.root, pointer)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("reference '%s' not found", ref)
+	}
+	s, ok := target.(*Schema)
+	if !ok {
+		return nil, fmt.Errorf("reference '%s' is not a schema", ref)
+	}
+	return s, nil
+}
+
+// validation holds the state of a single Validate call.
+type validation struct {
+	strict        bool
+	stopOnFailure bool
+	direction     Direction
+	errs          ValidationErrors
+	offset        int64           // The byte offset of the value being validated, or -1.
+	deferred      bool            // Set while the unknown properties of the object at deferredAt are checked by a combinator.
+	deferredAt    string          // The pointer of that object.
+	merged        *compiledSchema // The outermost schema whose allOf is applied to the object at mergedAt.
+	mergedAt      string
+}
+
+// fail records a failure of the value at pointer against s.
+func (v *validation) fail(s *compiledSchema, pointer string, format string, args ...interface{}) {
+	if v.done() {
+		return
+	}
+	err := ValidationError{Err: fmt.Errorf(format, args...), Field: pointer, Position: s.position}
+	if v.offset >= 0 {
+		err.Offset, err.HasOffset = v.offset, true
+	}
+	v.errs = append(v.errs, err)
+}
+
+// excluded reports whether the property s may not be sent in the direction being validated.
+func (v *validation) excluded(s *compiledSchema) bool {
+	return excluded(v.direction, s, 0)
+}
+
+// excluded reports whether the property s may not be sent in direction d: whether s or one
+// of its allOf schemas is readOnly, for a request, or writeOnly, for a response.
+func excluded(d Direction, s *compiledSchema, depth int) bool {
+	if s == nil || d == AnyDirection || depth > 32 {
+		return false
+	}
+	if d == RequestDirection && s.schema.ReadOnly || d == ResponseDirection && s.schema.WriteOnly {
+		return true
+	}
+	for _, sub := range s.allOf {
+		if excluded(d, sub, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludes reports whether s or one of its allOf schemas declares the property name as one
+// that may not be sent in direction d.
+func (s *compiledSchema) excludes(d Direction, name string) bool {
+	var find func(s *compiledSchema, depth int) bool
+	find = func(s *compiledSchema, depth int) bool {
+		if s == nil || depth > 32 {
+			return false
+		}
+		if excluded(d, s.properties[name], 0) {
+			return true
+		}
+		for _, sub := range s.allOf {
+			if find(sub, depth+1) {
+				return true
+			}
+		}
+		return false
+	}
+	return d != AnyDirection && find(s, 0)
+}
+
+// excludedProperty reports whether the property name of the object at pointer may not be
+// sent in the direction being validated, according to s or, as allOf merges the properties
+// of its schemas, to the outermost schema whose allOf is being applied to the object.
+func (v *validation) excludedProperty(s *compiledSchema, pointer, name string) bool {
+	if s.excludes(v.direction, name) {
+		return true
+	}
+	return v.merged != nil && v.mergedAt == pointer && v.merged.excludes(v.direction, name)
+}
+
+// mergeAllOf records that the allOf schemas of s are applied to the object at pointer, unless
+// those of an enclosing schema already are. It returns a function restoring the previous state.
+func (v *validation) mergeAllOf(s *compiledSchema, pointer string) (restore func()) {
+	if v.merged != nil && v.mergedAt == pointer {
+		return func() {}
+	}
+	merged, mergedAt := v.merged, v.mergedAt
+	v.merged, v.mergedAt = s, pointer
+	return func() {
+		v.merged, v.mergedAt = merged, mergedAt
+	}
+}
+
+// done reports whether validation should stop.
+func (v *validation) done() bool {
+	return v.stopOnFailure && len(v.errs) > 0
+}
+
+// passes reports whether value is valid against s, without recording any failure.
+func (v *validation) passes(s *compiledSchema, pointer string, value interface{}) bool {
+	probe := v.probe()
+	s.validate(probe, pointer, value)
+	return len(probe.errs) == 0
+}
+
+// probe returns a validation that records failures without reporting them, stopping at the first.
+func (v *validation) probe() *validation {
+	probe := *v
+	probe.stopOnFailure, probe.errs = true, nil
+	return &probe
+}
+
+// deferStrict leaves the unknown properties of the object at pointer to the caller, which
+// checks them once for a schema and its subschemas. It returns a function restoring the
+// previous state.
+func (v *validation) deferStrict(pointer string) (restore func()) {
+	deferred, deferredAt := v.deferred, v.deferredAt
+	v.deferred, v.deferredAt = true, pointer
+	return func() {
+		v.deferred, v.deferredAt = deferred, deferredAt
+	}
+}
+
+// strictAt reports whether unknown properties of the object at pointer are rejected where they are found.
+func (v *validation) strictAt(pointer string) bool {
+	return v.strict && !(v.deferred && v.deferredAt == pointer)
+}
+
+// formatCheckers are the string formats checked by default.
+var formatCheckers = map[string]func(string) error{
+	"date-time": func(s string) error {
+		i := strings.IndexAny(s, "Tt")
+		if i < 0 {
+			return errors.New("not a date-time")
+		}
+		if _, err := time.Parse("2006-01-02", s[:i]); err != nil {
+			return err
+		}
+		return checkTime(s[i+1:])
+	},
+	"date": func(s string) error {
+		_, err := time.Parse("2006-01-02", s)
+		return err
+	},
+	"time": checkTime,
+	"email": func(s string) error {
+		address, err := mail.ParseAddress(s)
+		if err == nil && address.Address != s {
+			err = errors.New("not a plain address")
+		}
+		return err
+	},
+	"uuid": func(s string) error {
+		if !uuidRegex.MatchString(s) {
+			return errors.New("not a UUID")
+		}
+		return nil
+	},
+	"ipv4": func(s string) error {
+		if ip := net.ParseIP(s); ip == nil || ip.To4() == nil {
+			return errors.New("not an IPv4 address")
+		}
+		return nil
+	},
+	"ipv6": func(s string) error {
+		if !strings.Contains(s, ":") || net.ParseIP(s) == nil {
+			return errors.New("not an IPv6 address")
+		}
+		return nil
+	},
+	"uri": func(s string) error {
+		u, err := url.Parse(s)
+		if err == nil && !u.IsAbs() {
+			err = errors.New("not an absolute URI")
+		}
+		return err
+	},
+	"hostname": func(s string) error {
+		if !hostnameRegex.MatchString(s) {
+			return errors.New("not a hostname")
+		}
+		return nil
+	},
+}
+
+// checkTime checks an RFC 3339 full-time, which time.Parse does not fully do: it rejects
+// leap seconds and lowercase separators, and accepts offsets such as +24:00.
+func checkTime(s string) error {
+	m := timeRegex.FindStringSubmatch(s)
+	if m == nil {
+		return errors.New("not a time")
+	}
+	hour, minute, second := atoi(m[1]), atoi(m[2]), atoi(m[3])
+	offsetHour, offsetMinute := atoi(m[6]), atoi(m[7])
+	if hour > 23 || minute > 59 || second > 60 || offsetHour > 23 || offsetMinute > 59 {
+		return errors.New("time out of range")
+	}
+	if second == 60 {
+		// A leap second is only inserted at 23:59:60 UTC.
+		utc := hour*60 + minute
+		if m[5] == "+" {
+			utc -= offsetHour*60 + offsetMinute
+		} else {
+			utc += offsetHour*60 + offsetMinute
+		}
+		if (utc+24*60)%(24*60) != 23*60+59 {
+			return errors.New("leap second not at 23:59 UTC")
+		}
+	}
+	return nil
+}
+
+// atoi converts the digits matched by timeRegex, or an empty string to 0.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+var (
+	timeRegex     = regexp.MustCompile(`^(\d{2}):(\d{2}):(\d{2})(\.\d+)?(?:[Zz]|([+-])(\d{2}):(\d{2}))$`)
+	uuidRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnameRegex = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
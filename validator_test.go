@@ -0,0 +1,174 @@
+package oas
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const benchmarkSchema = `{
+	"type": "object",
+	"required": ["id", "owner"],
+	"properties": {
+		"id": {"type": "string", "pattern": "^[a-z]{3}-[0-9]{4}$"},
+		"owner": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string", "minLength": 1},
+				"email": {"type": "string", "format": "email"},
+				"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}}
+			}
+		}
+	}
+}`
+
+var benchmarkValue = map[string]interface{}{
+	"id": "abc-1234",
+	"owner": map[string]interface{}{
+		"name":  "Ada",
+		"email": "ada@example.com",
+		"tags":  []interface{}{"admin", "owner"},
+	},
+}
+
+func benchmarkSchemaFixture(b *testing.B) *Schema {
+	s := &Schema{}
+	if err := json.Unmarshal([]byte(benchmarkSchema), s); err != nil {
+		b.Fatal(err)
+	}
+	return s
+}
+
+func BenchmarkSchemaValidate(b *testing.B) {
+	s := benchmarkSchemaFixture(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := s.Validate(benchmarkValue, true, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidatorValidate(b *testing.B) {
+	v, err := Compile(benchmarkSchemaFixture(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := v.Validate(benchmarkValue, true, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCompileReferences(t *testing.T) {
+	components := &Components{Schemas: map[string]*Schema{"Name": {Type: Types{"string"}}}}
+	s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{"name": {Ref: "#/components/schemas/Name"}}}
+	value := map[string]interface{}{"name": 1}
+
+	tests := []struct {
+		name string
+		opts []CompileOption
+		err  string // A substring of the expected compile error, or empty when the schema compiles.
+	}{
+		{name: "without components", err: "reference '#/components/schemas/Name' not found"},
+		{name: "with components", opts: []CompileOption{WithComponents(components)}},
+		{name: "missing component", opts: []CompileOption{WithComponents(&Components{})}, err: "not found"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := Compile(s, test.opts...)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("Compile() = %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := v.Validate(value, false, false); err == nil {
+				t.Error("Validate() = nil, want the referenced type to be checked")
+			}
+		})
+	}
+	if err := s.Validate(value, false, false); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Schema.Validate() = %v, want the unresolvable reference reported", err)
+	}
+}
+
+func TestDirection(t *testing.T) {
+	var components Components
+	if err := json.Unmarshal([]byte(`{"schemas": {
+		"Base": {"type": "object", "properties": {
+			"id": {"type": "string", "readOnly": true},
+			"password": {"type": "string", "writeOnly": true}
+		}},
+		"Id": {"type": "string", "readOnly": true},
+		"Name": {"type": "string"}
+	}}`), &components); err != nil {
+		t.Fatal(err)
+	}
+	schemas := map[string]string{
+		"properties": `{"type": "object", "required": ["id", "password"], "properties": {
+			"id": {"type": "string", "readOnly": true},
+			"password": {"type": "string", "writeOnly": true}
+		}}`,
+		"allOf": `{"allOf": [
+			{"$ref": "#/components/schemas/Base"},
+			{"type": "object", "required": ["id", "password", "name"], "properties": {"name": {"type": "string"}}}
+		]}`,
+		"allOf required": `{"allOf": [{"$ref": "#/components/schemas/Base"}], "required": ["id", "password"]}`,
+		"property allOf": `{"type": "object", "required": ["id"], "properties": {"id": {"allOf": [{"$ref": "#/components/schemas/Id"}]}}}`,
+		"property ref":   `{"type": "object", "required": ["name"], "properties": {"name": {"$ref": "#/components/schemas/Name", "readOnly": true}}}`,
+	}
+	tests := []struct {
+		name      string
+		schema    string
+		direction Direction
+		value     string
+		err       string // A substring of the expected error, or empty when the value is valid.
+	}{
+		{name: "read-only property missing from a request", schema: "properties", direction: RequestDirection, value: `{"password": "secret"}`},
+		{name: "read-only property in a request", schema: "properties", direction: RequestDirection, value: `{"id": "1", "password": "secret"}`, err: "'id' is read-only"},
+		{name: "write-only property in a response", schema: "properties", direction: ResponseDirection, value: `{"id": "1", "password": "secret"}`, err: "'password' is write-only"},
+		{name: "both required without a direction", schema: "properties", direction: AnyDirection, value: `{"id": "1"}`, err: "'password' is missing"},
+		{name: "allOf request", schema: "allOf", direction: RequestDirection, value: `{"password": "secret", "name": "a"}`},
+		{name: "allOf response", schema: "allOf", direction: ResponseDirection, value: `{"id": "1", "name": "a"}`},
+		{name: "allOf read-only property in a request", schema: "allOf", direction: RequestDirection, value: `{"id": "1", "password": "secret", "name": "a"}`, err: "'id' is read-only"},
+		{name: "allOf other property still required", schema: "allOf", direction: RequestDirection, value: `{"password": "secret"}`, err: "'name' is missing"},
+		{name: "required beside allOf", schema: "allOf required", direction: RequestDirection, value: `{"password": "secret"}`},
+		{name: "required beside allOf without a direction", schema: "allOf required", direction: AnyDirection, value: `{"password": "secret"}`, err: "'id' is missing"},
+		{name: "property allOf of a read-only schema", schema: "property allOf", direction: RequestDirection, value: `{}`},
+		{name: "property allOf in a request", schema: "property allOf", direction: RequestDirection, value: `{"id": "1"}`, err: "'id' is read-only"},
+		{name: "readOnly beside a reference", schema: "property ref", direction: RequestDirection, value: `{}`},
+		{name: "readOnly beside a reference in a response", schema: "property ref", direction: ResponseDirection, value: `{"name": 1}`, err: "expected string"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var s Schema
+			if err := json.Unmarshal([]byte(schemas[test.schema]), &s); err != nil {
+				t.Fatal(err)
+			}
+			var value interface{}
+			if err := json.Unmarshal([]byte(test.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			opts := []CompileOption{WithComponents(&components), WithDirection(test.direction)}
+			for method, err := range map[string]error{
+				"Validate":     s.Validate(value, false, false, opts...),
+				"ValidateJSON": s.ValidateJSON([]byte(test.value), false, false, opts...),
+			} {
+				if test.err == "" && err != nil {
+					t.Errorf("%s() = %v, want no error", method, err)
+				}
+				if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+					t.Errorf("%s() = %v, want an error containing %q", method, err, test.err)
+				}
+			}
+		})
+	}
+}
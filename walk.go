@@ -0,0 +1,236 @@
+package oas
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// WalkAction tells Walk how to proceed after a Visitor's Enter hook returns.
+type WalkAction int
+
+const (
+	WalkContinue WalkAction = iota // Visit the children of the node.
+	WalkSkip                       // Skip the children of the node. Leave is still called.
+	WalkStop                       // Stop walking the document immediately.
+)
+
+// Cursor describes a node visited by Walk.
+type Cursor struct {
+	Value   interface{} // The node, e.g. *OpenAPI, *Operation or *Schema.
+	Key     string      // The map key, field name or slice index of the node within its parent.
+	Pointer string      // The JSON Pointer of the node within the document.
+	Parent  *Cursor     // The cursor of the enclosing node, nil for the document itself.
+
+	set func(interface{})
+}
+
+// Replace replaces the node in its parent with v, which must have the same type as the node.
+// When called from Enter, Walk continues with the children of v.
+func (c *Cursor) Replace(v interface{}) error {
+	if c.set == nil {
+		return fmt.Errorf("%s: the document itself cannot be replaced", c.Pointer)
+	}
+	if reflect.TypeOf(v) != reflect.TypeOf(c.Value) || reflect.ValueOf(v).IsNil() {
+		return fmt.Errorf("%s: cannot replace %T with %T", c.Pointer, c.Value, v)
+	}
+	c.set(v)
+	c.Value = v
+	return nil
+}
+
+// Parents returns the enclosing nodes, starting with the document and ending with the direct parent.
+func (c *Cursor) Parents() []interface{} {
+	var parents []interface{}
+	for p := c.Parent; p != nil; p = p.Parent {
+		parents = append([]interface{}{p.Value}, parents...)
+	}
+	return parents
+}
+
+// Visitor holds the hooks called by Walk. Either hook may be nil.
+type Visitor struct {
+	Enter func(c *Cursor) WalkAction // Called before the children of a node are visited.
+	Leave func(c *Cursor)            // Called after the children of a node are visited.
+}
+
+// Walk visits every object of the document depth-first, in the order the fields are declared
+// and map keys sorted. A schema that contains itself, as a dereferenced recursive schema does,
+// is visited once per path from the document and is not descended into again.
+func Walk(doc *OpenAPI, v Visitor) {
+	if doc == nil {
+		return
+	}
+	w := &walker{visitor: v, schemas: map[*Schema]bool{}}
+	w.node(nil, "", "", doc, nil)
+}
+
+type walker struct {
+	visitor Visitor
+	stopped bool
+	schemas map[*Schema]bool
+}
+
+func (w *walker) node(parent *Cursor, key, pointer string, value interface{}, set func(interface{})) {
+	if w.stopped {
+		return
+	}
+	c := &Cursor{Value: value, Key: key, Pointer: pointer, Parent: parent, set: set}
+	action := WalkContinue
+	if w.visitor.Enter != nil {
+		action = w.visitor.Enter(c)
+	}
+	switch action {
+	case WalkStop:
+		w.stopped = true
+		return
+	case WalkContinue:
+		w.children(c)
+		if w.stopped {
+			return
+		}
+	}
+	if w.visitor.Leave != nil {
+		w.visitor.Leave(c)
+	}
+}
+
+func (w *walker) children(c *Cursor) {
+	switch n := c.Value.(type) {
+	case *OpenAPI:
+		walkField(w, c, "info", &n.Info)
+		walkField(w, c, "externalDocs", &n.ExternalDocs)
+		walkSlice(w, c, "servers", n.Servers)
+		walkSlice(w, c, "tags", n.Tags)
+		walkMap(w, c, "paths", n.Paths)
+		walkMap(w, c, "webhooks", n.Webhooks)
+		walkField(w, c, "components", &n.Components)
+		walkSlice(w, c, "security", n.Security)
+	case *Info:
+		walkField(w, c, "contact", &n.Contact)
+		walkField(w, c, "license", &n.License)
+	case *Server:
+		walkMap(w, c, "variables", n.Variables)
+	case *Tag:
+		walkField(w, c, "externalDocs", &n.ExternalDocs)
+	case *Path:
+		walkField(w, c, "get", &n.Get)
+		walkField(w, c, "put", &n.Put)
+		walkField(w, c, "post", &n.Post)
+		walkField(w, c, "delete", &n.Delete)
+		walkField(w, c, "options", &n.Options)
+		walkField(w, c, "head", &n.Head)
+		walkField(w, c, "patch", &n.Patch)
+		walkField(w, c, "trace", &n.Trace)
+		walkSlice(w, c, "servers", n.Servers)
+		walkSlice(w, c, "parameters", n.Parameters)
+	case *Operation:
+		walkField(w, c, "externalDocs", &n.ExternalDocs)
+		walkSlice(w, c, "parameters", n.Parameters)
+		walkField(w, c, "requestBody", &n.RequestBody)
+		walkMap(w, c, "responses", n.Responses)
+		walkMap(w, c, "callbacks", n.Callbacks)
+		walkSlice(w, c, "security", n.Security)
+		walkSlice(w, c, "servers", n.Servers)
+	case *Parameter:
+		walkField(w, c, "schema", &n.Schema)
+		walkMap(w, c, "examples", n.Examples)
+		walkMap(w, c, "content", n.Content)
+	case *RequestBody:
+		walkMap(w, c, "content", n.Content)
+	case *Response:
+		walkMap(w, c, "headers", n.Headers)
+		walkMap(w, c, "content", n.Content)
+		walkMap(w, c, "links", n.Links)
+	case *Header:
+		walkField(w, c, "schema", &n.Schema)
+		walkMap(w, c, "examples", n.Examples)
+		walkMap(w, c, "content", n.Content)
+	case *MediaType:
+		walkField(w, c, "schema", &n.Schema)
+		walkMap(w, c, "examples", n.Examples)
+		walkMap(w, c, "encoding", n.Encoding)
+	case *Encoding:
+		walkMap(w, c, "headers", n.Headers)
+	case *Link:
+		walkField(w, c, "server", &n.Server)
+	case *Callback:
+		walkMap(w, c, "", n.Expression)
+	case *Components:
+		walkMap(w, c, "schemas", n.Schemas)
+		walkMap(w, c, "responses", n.Responses)
+		walkMap(w, c, "parameters", n.Parameters)
+		walkMap(w, c, "examples", n.Examples)
+		walkMap(w, c, "requestBodies", n.RequestBodies)
+		walkMap(w, c, "headers", n.Headers)
+		walkMap(w, c, "securitySchemes", n.SecuritySchemes)
+		walkMap(w, c, "links", n.Links)
+		walkMap(w, c, "callbacks", n.Callbacks)
+		walkMap(w, c, "pathItems", n.PathItems)
+	case *SecurityScheme:
+		walkField(w, c, "flows", &n.Flows)
+	case *OAuthFlows:
+		walkField(w, c, "implicit", &n.Implicit)
+		walkField(w, c, "password", &n.Password)
+		walkField(w, c, "clientCredentials", &n.ClientCredentials)
+		walkField(w, c, "authorizationCode", &n.AuthorizationCode)
+	case *Schema:
+		if w.schemas[n] {
+			return
+		}
+		w.schemas[n] = true
+		defer delete(w.schemas, n)
+		walkSlice(w, c, "allOf", n.AllOf)
+		walkSlice(w, c, "oneOf", n.OneOf)
+		walkSlice(w, c, "anyOf", n.AnyOf)
+		walkField(w, c, "not", &n.Not)
+		walkField(w, c, "if", &n.If)
+		walkField(w, c, "then", &n.Then)
+		walkField(w, c, "else", &n.Else)
+		walkMap(w, c, "dependentSchemas", n.DependentSchemas)
+		walkSlice(w, c, "prefixItems", n.PrefixItems)
+		walkField(w, c, "items", &n.Items)
+		walkField(w, c, "contains", &n.Contains)
+		walkField(w, c, "unevaluatedItems", &n.UnevaluatedItems)
+		walkMap(w, c, "properties", n.Properties)
+		walkMap(w, c, "patternProperties", n.PatternProperties)
+		walkField(w, c, "additionalProperties", &n.AdditionalProperties)
+		walkField(w, c, "propertyNames", &n.PropertyNames)
+		walkField(w, c, "unevaluatedProperties", &n.UnevaluatedProperties)
+		walkField(w, c, "contentSchema", &n.ContentSchema)
+		walkField(w, c, "xml", &n.XML)
+		walkField(w, c, "externalDocs", &n.ExternalDocs)
+		walkMap(w, c, "$defs", n.Defs)
+	}
+}
+
+func walkField[T any](w *walker, parent *Cursor, name string, field **T) {
+	if *field == nil {
+		return
+	}
+	w.node(parent, name, parent.Pointer+"/"+name, *field, func(v interface{}) { *field = v.(*T) })
+}
+
+func walkSlice[T any](w *walker, parent *Cursor, name string, s []*T) {
+	for i := range s {
+		if s[i] == nil {
+			continue
+		}
+		key := strconv.Itoa(i)
+		w.node(parent, key, parent.Pointer+"/"+name+"/"+key, s[i], func(v interface{}) { s[i] = v.(*T) })
+	}
+}
+
+// walkMap walks the entries of the map field name. An empty name walks entries inlined in the parent.
+func walkMap[T any](w *walker, parent *Cursor, name string, m map[string]*T) {
+	prefix := parent.Pointer + "/"
+	if name != "" {
+		prefix += name + "/"
+	}
+	for _, key := range sortedKeys(m) {
+		if m[key] == nil {
+			continue
+		}
+		w.node(parent, key, prefix+pointerEscape(key), m[key], func(v interface{}) { m[key] = v.(*T) })
+	}
+}
@@ -0,0 +1,160 @@
+package oas
+
+import (
+	"strings"
+	"testing"
+)
+
+const walkDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Events", "version": "1"},
+	"paths": {"/subscriptions": {"post": {
+		"parameters": [{"name": "id", "in": "query", "schema": {"type": "string"}}],
+		"responses": {"201": {
+			"description": "subscribed",
+			"headers": {"Location": {"schema": {"type": "string"}}}
+		}},
+		"callbacks": {"onEvent": {"{$request.body#/url}": {"post": {
+			"responses": {"200": {"description": "received"}}
+		}}}}
+	}}},
+	"components": {"schemas": {"Event": {"type": "object", "properties": {
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}}}}
+}`
+
+func TestWalk(t *testing.T) {
+	doc, err := LoadJSON([]byte(walkDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		skip  string // The pointer of the node whose children are skipped.
+		stop  string // The pointer of the node at which the walk stops.
+		enter []string
+		leave []string // The nodes left, or nil to leave them in the reverse order of a tree walk.
+	}{
+		{
+			name: "every node",
+			enter: []string{
+				"",
+				"/info",
+				"/paths/~1subscriptions",
+				"/paths/~1subscriptions/post",
+				"/paths/~1subscriptions/post/parameters/0",
+				"/paths/~1subscriptions/post/parameters/0/schema",
+				"/paths/~1subscriptions/post/responses/201",
+				"/paths/~1subscriptions/post/responses/201/headers/Location",
+				"/paths/~1subscriptions/post/responses/201/headers/Location/schema",
+				"/paths/~1subscriptions/post/callbacks/onEvent",
+				"/paths/~1subscriptions/post/callbacks/onEvent/{$request.body#~1url}",
+				"/paths/~1subscriptions/post/callbacks/onEvent/{$request.body#~1url}/post",
+				"/paths/~1subscriptions/post/callbacks/onEvent/{$request.body#~1url}/post/responses/200",
+				"/components",
+				"/components/schemas/Event",
+				"/components/schemas/Event/properties/tags",
+				"/components/schemas/Event/properties/tags/items",
+			},
+		},
+		{
+			name: "skip",
+			skip: "/paths/~1subscriptions/post",
+			enter: []string{
+				"",
+				"/info",
+				"/paths/~1subscriptions",
+				"/paths/~1subscriptions/post",
+				"/components",
+				"/components/schemas/Event",
+				"/components/schemas/Event/properties/tags",
+				"/components/schemas/Event/properties/tags/items",
+			},
+		},
+		{
+			name:  "stop",
+			stop:  "/paths/~1subscriptions/post/parameters/0",
+			enter: []string{"", "/info", "/paths/~1subscriptions", "/paths/~1subscriptions/post", "/paths/~1subscriptions/post/parameters/0"},
+			leave: []string{"/info"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var enter, leave []string
+			Walk(doc, Visitor{
+				Enter: func(c *Cursor) WalkAction {
+					enter = append(enter, c.Pointer)
+					switch {
+					case test.skip != "" && c.Pointer == test.skip:
+						return WalkSkip
+					case test.stop != "" && c.Pointer == test.stop:
+						return WalkStop
+					}
+					return WalkContinue
+				},
+				Leave: func(c *Cursor) {
+					leave = append(leave, c.Pointer)
+				},
+			})
+			if strings.Join(enter, "\n") != strings.Join(test.enter, "\n") {
+				t.Errorf("entered\n%s\nwant\n%s", strings.Join(enter, "\n"), strings.Join(test.enter, "\n"))
+			}
+			want := test.leave
+			if want == nil {
+				// Every node is left, and a node is left after its children.
+				want = postOrder(test.enter)
+			}
+			if strings.Join(leave, "\n") != strings.Join(want, "\n") {
+				t.Errorf("left\n%s\nwant\n%s", strings.Join(leave, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+// postOrder returns the pointers of a pre-order walk in the order the nodes are left.
+func postOrder(pointers []string) []string {
+	var order, stack []string
+	for _, pointer := range pointers {
+		for len(stack) > 0 && !strings.HasPrefix(pointer, stack[len(stack)-1]+"/") {
+			order, stack = append(order, stack[len(stack)-1]), stack[:len(stack)-1]
+		}
+		stack = append(stack, pointer)
+	}
+	for len(stack) > 0 {
+		order, stack = append(order, stack[len(stack)-1]), stack[:len(stack)-1]
+	}
+	return order
+}
+
+func TestCursorReplace(t *testing.T) {
+	doc, err := LoadJSON([]byte(walkDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replacement := &Schema{Type: Types{"integer"}}
+	var parents []interface{}
+	Walk(doc, Visitor{Enter: func(c *Cursor) WalkAction {
+		switch c.Pointer {
+		case "":
+			if err := c.Replace(&OpenAPI{}); err == nil {
+				t.Error("Replace() of the document = nil, want an error")
+			}
+		case "/paths/~1subscriptions/post/parameters/0":
+			if err := c.Replace(&Schema{}); err == nil {
+				t.Error("Replace() of a parameter with a schema = nil, want an error")
+			}
+		case "/paths/~1subscriptions/post/parameters/0/schema":
+			if err := c.Replace(replacement); err != nil {
+				t.Error(err)
+			}
+			parents = c.Parents()
+		}
+		return WalkContinue
+	}})
+	if doc.Paths["/subscriptions"].Post.Parameters[0].Schema != replacement {
+		t.Error("the parameter schema was not replaced")
+	}
+	if len(parents) != 4 || parents[0] != doc || parents[3] != doc.Paths["/subscriptions"].Post.Parameters[0] {
+		t.Errorf("Parents() = %v, want the document, path, operation and parameter", parents)
+	}
+}
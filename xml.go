@@ -0,0 +1,557 @@
+package oas
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xmlNode is an element of a decoded XML document.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+// parseXML parses data into a tree of elements, returning its root element.
+func parseXML(data []byte) (*xmlNode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding XML: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name, attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, errors.New("error decoding XML: multiple root elements")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, errors.New("error decoding XML: no root element")
+	}
+	return root, nil
+}
+
+// xmlOf returns the XML object of a schema, or an empty one.
+func xmlOf(s *Schema) *XML {
+	if s == nil || s.XML == nil {
+		return &XML{}
+	}
+	return s.XML
+}
+
+// xmlKind returns "object", "array" or "" for a scalar, according to the type of a schema
+// or, when it has none, the keywords it uses.
+func (o *OpenAPI) xmlKind(s *Schema) string {
+	switch {
+	case s == nil:
+		return ""
+	case s.Type.Includes("object"):
+		return "object"
+	case s.Type.Includes("array"):
+		return "array"
+	case len(s.Type) == 0 && len(o.xmlProperties(s)) > 0:
+		return "object"
+	case len(s.Type) == 0 && s.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// xmlProperties returns the properties of a schema, including those of its allOf schemas.
+func (o *OpenAPI) xmlProperties(s *Schema) map[string]*Schema {
+	properties := map[string]*Schema{}
+	var collect func(s *Schema, depth int)
+	collect = func(s *Schema, depth int) {
+		if s = o.resolveSchema(s); s == nil || depth > 32 {
+			return
+		}
+		for _, sub := range s.AllOf {
+			collect(sub, depth+1)
+		}
+		for name, property := range s.Properties {
+			properties[name] = property
+		}
+	}
+	collect(s, 0)
+	return properties
+}
+
+// xmlName returns the element name of a schema used as the root of a document: its XML
+// name, or the name of the component it references.
+func (o *OpenAPI) xmlName(s *Schema) string {
+	if name := xmlOf(o.resolveSchema(s)).Name; name != "" {
+		return name
+	}
+	if s != nil && s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+	return ""
+}
+
+// matchesXMLName reports whether name is the local name expected in the namespace of x.
+func matchesXMLName(name xml.Name, local string, x *XML) bool {
+	return name.Local == local && (x.Namespace == "" || name.Space == "" || name.Space == x.Namespace)
+}
+
+// DecodeXML decodes an XML document into the value the schema s describes, so that it can be
+// validated. The XML objects of the schema and its properties are followed: properties are
+// read from the elements or, with Attribute, the attributes named after them or their XML
+// name, arrays are read from repeated elements, inside a wrapper element when Wrapped is
+// set, and text is converted to the type of its schema. Elements and attributes the schema
+// does not describe are kept as properties so that strict validation reports them. When the
+// schema names its root element, the document must use that name.
+func (o *OpenAPI) DecodeXML(s *Schema, data []byte) (interface{}, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+	resolved := o.resolveSchema(s)
+	if x := xmlOf(resolved); x.Name != "" && !matchesXMLName(root.name, x.Name, x) {
+		return nil, fmt.Errorf("root element is '%s', expected '%s'", root.name.Local, x.Name)
+	}
+	return o.xmlValue(resolved, root), nil
+}
+
+// xmlValue converts an element to the value the schema s describes.
+func (o *OpenAPI) xmlValue(s *Schema, n *xmlNode) interface{} {
+	s = o.resolveSchema(s)
+	switch o.xmlKind(s) {
+	case "object":
+		return o.xmlObject(s, n)
+	case "array":
+		// An array outside of a property is the list of the children of its element.
+		items := o.resolveSchema(s.Items)
+		values := []interface{}{}
+		for _, child := range n.children {
+			values = append(values, o.xmlValue(items, child))
+		}
+		return values
+	}
+	if s == nil && (len(n.children) > 0 || len(n.attrs) > 0) {
+		return o.xmlObject(nil, n)
+	}
+	text := n.text
+	if s == nil || !s.Type.Includes("string") {
+		text = strings.TrimSpace(text)
+	}
+	return coerce(typesOf(s), text)
+}
+
+// xmlObject converts the attributes and children of an element to the properties of the
+// object schema s.
+func (o *OpenAPI) xmlObject(s *Schema, n *xmlNode) map[string]interface{} {
+	object := map[string]interface{}{}
+	usedAttrs := make([]bool, len(n.attrs))
+	usedChildren := make([]bool, len(n.children))
+	properties := o.xmlProperties(s)
+	for _, name := range sortedKeys(properties) {
+		property := o.resolveSchema(properties[name])
+		x := xmlOf(property)
+		elementName := name
+		if x.Name != "" {
+			elementName = x.Name
+		}
+
+		if x.Attribute {
+			for i, attr := range n.attrs {
+				if !usedAttrs[i] && matchesXMLName(attr.Name, elementName, x) {
+					usedAttrs[i] = true
+					object[name] = coerce(typesOf(property), attr.Value)
+					break
+				}
+			}
+			continue
+		}
+
+		if o.xmlKind(property) != "array" {
+			for i, child := range n.children {
+				if !usedChildren[i] && matchesXMLName(child.name, elementName, x) {
+					usedChildren[i] = true
+					object[name] = o.xmlValue(property, child)
+					break
+				}
+			}
+			continue
+		}
+
+		items := o.resolveSchema(property.Items)
+		itemX := xmlOf(items)
+		itemName := elementName
+		if itemX.Name != "" {
+			itemName = itemX.Name
+		}
+		children, used := n.children, usedChildren
+		if x.Wrapped {
+			wrapper := -1
+			for i, child := range n.children {
+				if !usedChildren[i] && matchesXMLName(child.name, elementName, x) {
+					wrapper = i
+					break
+				}
+			}
+			if wrapper < 0 {
+				continue
+			}
+			usedChildren[wrapper] = true
+			children = n.children[wrapper].children
+			used = make([]bool, len(children))
+		}
+		values := []interface{}{}
+		for i, child := range children {
+			if !used[i] && matchesXMLName(child.name, itemName, itemX) {
+				used[i] = true
+				values = append(values, o.xmlValue(items, child))
+			}
+		}
+		if len(values) > 0 || x.Wrapped {
+			object[name] = values
+		}
+	}
+
+	for i, attr := range n.attrs {
+		if usedAttrs[i] || attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		if _, ok := object[attr.Name.Local]; !ok {
+			object[attr.Name.Local] = attr.Value
+		}
+	}
+	for i, child := range n.children {
+		if usedChildren[i] {
+			continue
+		}
+		if _, ok := object[child.name.Local]; !ok {
+			object[child.name.Local] = o.xmlValue(nil, child)
+		}
+	}
+	return object
+}
+
+// EncodeXML renders value as an XML document following the XML objects of the schema s, as
+// DecodeXML reads them. Go values are converted as for validation, so structs are encoded
+// by their json tags. The root element is named after the XML name of s or the component it
+// references. Namespaces are declared on the elements whose schema sets them, and elements
+// and attributes take the prefix of their schema.
+func (o *OpenAPI) EncodeXML(s *Schema, value interface{}) ([]byte, error) {
+	name := o.xmlName(s)
+	if name == "" {
+		return nil, errors.New("schema does not name its root element; set its xml name")
+	}
+	var buf bytes.Buffer
+	if err := o.encodeXML(&buf, s, name, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeXML writes value as an element with the given name.
+func (o *OpenAPI) encodeXML(buf *bytes.Buffer, s *Schema, name string, value interface{}) error {
+	s = o.resolveSchema(s)
+	x := xmlOf(s)
+	format := ""
+	if s != nil {
+		format = s.Format
+	}
+	value = goValue(value, format)
+	element, err := qualifiedXMLName(x, name)
+	if err != nil {
+		return err
+	}
+	namespaces := map[string]string{}
+	declareXMLNamespace(namespaces, x)
+
+	if value == nil {
+		buf.WriteString("<" + element)
+		writeXMLNamespaces(buf, namespaces)
+		buf.WriteString("/>")
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	kind := o.xmlKind(s)
+	if kind == "" && s == nil {
+		switch rv.Kind() {
+		case reflect.Map:
+			kind = "object"
+		case reflect.Slice, reflect.Array:
+			kind = "array"
+		}
+	}
+
+	switch {
+	case kind == "object" && rv.Kind() == reflect.Map:
+		return o.encodeXMLObject(buf, s, element, namespaces, rv)
+	case kind == "array" && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array):
+		// An array outside of a property is an element holding one element per item.
+		var items *Schema
+		if s != nil {
+			items = s.Items
+		}
+		itemName := o.xmlName(items)
+		if itemName == "" {
+			itemName = name
+		}
+		buf.WriteString("<" + element)
+		writeXMLNamespaces(buf, namespaces)
+		buf.WriteString(">")
+		for i := 0; i < rv.Len(); i++ {
+			if err := o.encodeXML(buf, items, itemName, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("</" + element + ">")
+		return nil
+	case rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		return fmt.Errorf("cannot encode %s as the %s element '%s'", rv.Kind(), kindName(kind), name)
+	}
+
+	buf.WriteString("<" + element)
+	writeXMLNamespaces(buf, namespaces)
+	buf.WriteString(">")
+	if err := xml.EscapeText(buf, []byte(xmlText(value))); err != nil {
+		return err
+	}
+	buf.WriteString("</" + element + ">")
+	return nil
+}
+
+// kindName describes the kind xmlKind returns.
+func kindName(kind string) string {
+	if kind == "" {
+		return "scalar"
+	}
+	return kind
+}
+
+// encodeXMLObject writes the map value as an element holding the properties of the object
+// schema s, followed by the entries the schema does not declare.
+func (o *OpenAPI) encodeXMLObject(buf *bytes.Buffer, s *Schema, element string, namespaces map[string]string, value reflect.Value) error {
+	properties := o.xmlProperties(s)
+	lookup := func(name string) (interface{}, bool) {
+		v := mapIndex(value, name)
+		if !v.IsValid() {
+			return nil, false
+		}
+		return v.Interface(), true
+	}
+
+	var attrs bytes.Buffer
+	for _, name := range sortedKeys(properties) {
+		property := o.resolveSchema(properties[name])
+		x := xmlOf(property)
+		if !x.Attribute {
+			continue
+		}
+		v, ok := lookup(name)
+		if v = goValue(v, property.Format); !ok || v == nil {
+			continue
+		}
+		attrName := name
+		if x.Name != "" {
+			attrName = x.Name
+		}
+		qualified, err := qualifiedXMLName(x, attrName)
+		if err != nil {
+			return err
+		}
+		declareXMLNamespace(namespaces, x)
+		attrs.WriteString(" " + qualified + `="`)
+		if err := xml.EscapeText(&attrs, []byte(xmlText(v))); err != nil {
+			return err
+		}
+		attrs.WriteString(`"`)
+	}
+	buf.WriteString("<" + element)
+	writeXMLNamespaces(buf, namespaces)
+	buf.Write(attrs.Bytes())
+	buf.WriteString(">")
+
+	for _, name := range sortedKeys(properties) {
+		property := o.resolveSchema(properties[name])
+		x := xmlOf(property)
+		v, ok := lookup(name)
+		if !ok || x.Attribute {
+			continue
+		}
+		elementName := name
+		if x.Name != "" {
+			elementName = x.Name
+		}
+		if err := o.encodeXMLProperty(buf, property, elementName, v); err != nil {
+			return err
+		}
+	}
+
+	var additional []string
+	for _, key := range value.MapKeys() {
+		if key.Kind() != reflect.String {
+			continue
+		}
+		if _, ok := properties[key.String()]; !ok {
+			additional = append(additional, key.String())
+		}
+	}
+	sort.Strings(additional)
+	for _, name := range additional {
+		v, _ := lookup(name)
+		if err := o.encodeXML(buf, nil, name, v); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("</" + element + ">")
+	return nil
+}
+
+// encodeXMLProperty writes a property. Arrays are written as one element per item, inside
+// a wrapper element when the property is Wrapped; other values as a single element.
+func (o *OpenAPI) encodeXMLProperty(buf *bytes.Buffer, property *Schema, name string, value interface{}) error {
+	if o.xmlKind(property) != "array" {
+		return o.encodeXML(buf, property, name, value)
+	}
+	value = goValue(value, "")
+	rv := reflect.ValueOf(value)
+	if value == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return o.encodeXML(buf, property, name, value)
+	}
+
+	x := xmlOf(property)
+	itemName := name
+	if itemX := xmlOf(o.resolveSchema(property.Items)); itemX.Name != "" {
+		itemName = itemX.Name
+	}
+	element, err := qualifiedXMLName(x, name)
+	if err != nil {
+		return err
+	}
+	if x.Wrapped {
+		namespaces := map[string]string{}
+		declareXMLNamespace(namespaces, x)
+		buf.WriteString("<" + element)
+		writeXMLNamespaces(buf, namespaces)
+		buf.WriteString(">")
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if err := o.encodeXML(buf, property.Items, itemName, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	if x.Wrapped {
+		buf.WriteString("</" + element + ">")
+	}
+	return nil
+}
+
+// qualifiedXMLName returns name with the prefix of x. Names come from schemas and from the
+// keys of encoded values, so both are checked to be XML names before they are written.
+func qualifiedXMLName(x *XML, name string) (string, error) {
+	if !isXMLName(name) {
+		return "", fmt.Errorf("'%s' is not a valid XML name", name)
+	}
+	if x.Prefix != "" {
+		if !isXMLName(x.Prefix) || strings.Contains(x.Prefix, ":") {
+			return "", fmt.Errorf("'%s' is not a valid XML namespace prefix", x.Prefix)
+		}
+		return x.Prefix + ":" + name, nil
+	}
+	return name, nil
+}
+
+// isXMLName reports whether name matches the Name production of XML 1.0.
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !isXMLNameStartChar(r) && (i == 0 || !isXMLNameChar(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isXMLNameStartChar reports whether r matches the NameStartChar production of XML 1.0.
+func isXMLNameStartChar(r rune) bool {
+	switch {
+	case r == ':' || r == '_' || 'A' <= r && r <= 'Z' || 'a' <= r && r <= 'z':
+		return true
+	case 0xC0 <= r && r <= 0xD6, 0xD8 <= r && r <= 0xF6, 0xF8 <= r && r <= 0x2FF,
+		0x370 <= r && r <= 0x37D, 0x37F <= r && r <= 0x1FFF, 0x200C <= r && r <= 0x200D,
+		0x2070 <= r && r <= 0x218F, 0x2C00 <= r && r <= 0x2FEF, 0x3001 <= r && r <= 0xD7FF,
+		0xF900 <= r && r <= 0xFDCF, 0xFDF0 <= r && r <= 0xFFFD, 0x10000 <= r && r <= 0xEFFFF:
+		return true
+	}
+	return false
+}
+
+// isXMLNameChar reports whether r matches the NameChar production of XML 1.0.
+func isXMLNameChar(r rune) bool {
+	return isXMLNameStartChar(r) || r == '-' || r == '.' || '0' <= r && r <= '9' || r == 0xB7 ||
+		0x300 <= r && r <= 0x36F || 0x203F <= r && r <= 0x2040
+}
+
+// declareXMLNamespace records the namespace of x, keyed by its prefix.
+func declareXMLNamespace(namespaces map[string]string, x *XML) {
+	if x.Namespace != "" {
+		namespaces[x.Prefix] = x.Namespace
+	}
+}
+
+// writeXMLNamespaces writes the namespace declarations of an element.
+func writeXMLNamespaces(buf *bytes.Buffer, namespaces map[string]string) {
+	for _, prefix := range sortedKeys(namespaces) {
+		attr := "xmlns"
+		if prefix != "" {
+			attr += ":" + prefix
+		}
+		buf.WriteString(" " + attr + `="`)
+		xml.EscapeText(buf, []byte(namespaces[prefix]))
+		buf.WriteString(`"`)
+	}
+}
+
+// xmlText returns the text of a scalar value.
+func xmlText(value interface{}) string {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,105 @@
+package oas
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const xmlDocument = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Pets", "version": "1"},
+	"paths": {},
+	"components": {"schemas": {
+		"Pet": {
+			"type": "object",
+			"xml": {"name": "pet", "namespace": "https://example.com/pets", "prefix": "p"},
+			"properties": {
+				"id": {"type": "integer", "xml": {"attribute": true}},
+				"name": {"type": "string"},
+				"tags": {"type": "array", "xml": {"wrapped": true}, "items": {"type": "string", "xml": {"name": "tag"}}}
+			}
+		},
+		"BadName": {"type": "object", "xml": {"name": "pet name"}},
+		"BadPrefix": {"type": "object", "xml": {"name": "pet", "prefix": "a:b"}}
+	}}
+}`
+
+func TestEncodeXML(t *testing.T) {
+	doc, err := LoadJSON([]byte(xmlDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		schema string
+		value  interface{}
+		want   string
+		err    string // A substring of the expected error, or empty when the value encodes.
+	}{
+		{
+			name:   "object",
+			schema: "Pet",
+			value:  map[string]interface{}{"id": 1, "name": "Rex & co", "tags": []interface{}{"a", "b"}},
+			want:   `<p:pet xmlns:p="https://example.com/pets" id="1"><name>Rex &amp; co</name><tags><tag>a</tag><tag>b</tag></tags></p:pet>`,
+		},
+		{
+			name:   "additional property",
+			schema: "Pet",
+			value:  map[string]interface{}{"name": "Rex", "color": "brown"},
+			want:   `<p:pet xmlns:p="https://example.com/pets"><name>Rex</name><color>brown</color></p:pet>`,
+		},
+		{
+			name:   "additional property that is not a name",
+			schema: "Pet",
+			value:  map[string]interface{}{"x><admin>true</admin><y": "1"},
+			err:    "not a valid XML name",
+		},
+		{name: "element name with a space", schema: "BadName", value: map[string]interface{}{}, err: "'pet name' is not a valid XML name"},
+		{name: "prefix with a colon", schema: "BadPrefix", value: map[string]interface{}{}, err: "'a:b' is not a valid XML namespace prefix"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := doc.EncodeXML(&Schema{Ref: "#/components/schemas/" + test.schema}, test.value)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("EncodeXML() = %s, %v, want an error containing %q", data, err, test.err)
+				}
+				return
+			}
+			if err != nil || string(data) != test.want {
+				t.Errorf("EncodeXML() = %s, %v, want %s", data, err, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeXML(t *testing.T) {
+	doc, err := LoadJSON([]byte(xmlDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pet := &Schema{Ref: "#/components/schemas/Pet"}
+	got, err := doc.DecodeXML(pet, []byte(`<p:pet xmlns:p="https://example.com/pets" id="7"><name>Rex</name><tags><tag>a</tag></tags></p:pet>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"id": int64(7), "name": "Rex", "tags": []interface{}{"a"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeXML() = %#v, want %#v", got, want)
+	}
+	if _, err := doc.DecodeXML(pet, []byte(`<dog/>`)); err == nil || !strings.Contains(err.Error(), "expected 'pet'") {
+		t.Errorf("DecodeXML() of another root element = %v, want an error", err)
+	}
+}
+
+func TestIsXMLName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"pet": true, "_pet": true, "p:pet": true, "pet-2.0": true, "été": true,
+		"": false, "2pet": false, "-pet": false, "pet name": false, "pet>": false, `pet"`: false,
+	} {
+		if got := isXMLName(name); got != want {
+			t.Errorf("isXMLName(%q) = %t, want %t", name, got, want)
+		}
+	}
+}